@@ -1,20 +1,39 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"strings"
 
+	"irptools/tools/analyze"
+	"irptools/tools/compact"
+	"irptools/tools/diff"
+	export_csv "irptools/tools/export/csv"
 	export_fz "irptools/tools/export/fz"
+	export_fz_universal "irptools/tools/export/fz_universal"
+	export_pronto "irptools/tools/export/pronto"
+	export_template "irptools/tools/export/template"
 	"irptools/tools/filter"
+	"irptools/tools/index"
+	"irptools/tools/merge"
 	"irptools/tools/parse"
+	"irptools/tools/pipeline"
+	"irptools/tools/plan"
+	"irptools/tools/serve"
 	"irptools/tools/stat"
+	"irptools/tools/stat_diff"
+	"irptools/tools/verify"
 	"irptools/utils/alg"
 	"irptools/utils/errs"
+	"irptools/utils/fs"
+	"irptools/utils/misc"
 )
 
 func main() {
@@ -22,16 +41,41 @@ func main() {
 	const defaultCmd = ""
 	const defaultCfg = "cfg_$cmd$.json"
 
-	cmds := map[string]func(ctx context.Context, cfg string) error{
-		"parse":     makeExecCmdFn(parse.Main, parse.LoadConfig),
-		"stat":      makeExecCmdFn(stat.Main, stat.LoadConfig),
-		"filter":    makeExecCmdFn(filter.Main, filter.LoadConfig),
-		"export_fz": makeExecCmdFn(export_fz.Main, export_fz.LoadConfig),
+	cmds := map[string]command{
+		"parse":               makeExecCmdFn(parse.Main, parse.LoadConfig),
+		"stat":                makeExecCmdFn(stat.Main, stat.LoadConfig),
+		"stat_diff":           makeExecCmdFn(stat_diff.Main, stat_diff.LoadConfig),
+		"filter":              makeExecCmdFn(filter.Main, filter.LoadConfig),
+		"plan":                makeExecCmdFn(plan.Main, plan.LoadConfig),
+		"index":               makeExecCmdFn(index.Main, index.LoadConfig),
+		"serve":               makeExecCmdFn(serve.Main, serve.LoadConfig),
+		"pipeline":            makeExecCmdFn(pipeline.Main, pipeline.LoadConfig),
+		"compact":             makeExecCmdFn(compact.Main, compact.LoadConfig),
+		"verify":              makeExecCmdFn(verify.Main, verify.LoadConfig),
+		"analyze":             makeExecCmdFn(analyze.Main, analyze.LoadConfig),
+		"diff":                makeExecCmdFn(diff.Main, diff.LoadConfig),
+		"merge":               makeExecCmdFn(merge.Main, merge.LoadConfig),
+		"export_fz":           makeExecCmdFn(export_fz.Main, export_fz.LoadConfig),
+		"export_csv":          makeExecCmdFn(export_csv.Main, export_csv.LoadConfig),
+		"export_fz_universal": makeExecCmdFn(export_fz_universal.Main, export_fz_universal.LoadConfig),
+		"export_template":     makeExecCmdFn(export_template.Main, export_template.LoadConfig),
+	}
+
+	streams := map[string]func(ctx context.Context, cfg string, overrides []misc.ConfigOverride, printCfg bool, in io.Reader, out io.Writer) error{
+		"parse":         makeStreamCmdFn(parse.Stream, parse.LoadStreamConfig),
+		"filter":        makeStreamCmdFn(filter.Stream, filter.LoadStreamConfig),
+		"export_fz":     makeStreamCmdFn(export_fz.Stream, export_fz.LoadStreamConfig),
+		"export_csv":    makeStreamCmdFn(export_csv.Stream, export_csv.LoadStreamConfig),
+		"export_pronto": makeStreamCmdFn(export_pronto.Stream, export_pronto.LoadStreamConfig),
 	}
 
 	var cmdLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-	cmd := cmdLine.String("cmd", defaultCmd, fmt.Sprintf("command: %s", alg.MapKeys(cmds)))
+	cmd := cmdLine.String("cmd", defaultCmd, fmt.Sprintf("command: %s, or %s [command...]", alg.MapKeys(cmds), []string{cmdSchema, cmdInit}))
 	cfg := cmdLine.String("cfg", defaultCfg, "config")
+	printCfg := cmdLine.Bool("print-config", false, "print effective config and exit")
+	var overrides configOverridesFlag
+	cmdLine.Var(&overrides, "set", "override config field, may be repeated: -set path.to.field=value; value is json if it parses, quote it to force a string: -set 'name=\"true\"'")
+	stream := cmdLine.Bool("stream", false, fmt.Sprintf("read and write signals as json lines on stdin/stdout: %s; filter takes its predicate from -cfg or -set 'filter={...}'", alg.MapKeys(streams)))
 	err := cmdLine.Parse(os.Args[1:])
 	if err != nil {
 		log.Fatal(err)
@@ -43,35 +87,129 @@ func main() {
 	l := log.Default()
 	l.Println("cmd =", *cmd)
 	l.Println("cfg =", *cfg)
-
-	execute, ok := cmds[*cmd]
-	if !ok {
-		cmdLine.Usage()
-		l.Fatalf("FAILED: Unknown command: '%s'", *cmd)
-		return
+	for _, o := range overrides {
+		l.Println("set =", strings.Join(o.Path, "."))
 	}
 
 	ctx := context.Background()
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
 	defer stop()
-	err = execute(ctx, *cfg)
+
+	if *stream {
+		executeStream, ok := streams[*cmd]
+		if !ok {
+			cmdLine.Usage()
+			l.Fatalf("FAILED: Unknown stream command: '%s'", *cmd)
+			return
+		}
+
+		if !isFlagSet(cmdLine, "cfg") {
+			*cfg = ""
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		err = errs.Join(executeStream(ctx, *cfg, overrides, *printCfg, bufio.NewReader(os.Stdin), out), out.Flush())
+		if err != nil {
+			l.Fatalf("FAILED: cmd = '%s': %v", *cmd, err)
+			return
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "DONE")
+		return
+	}
+
+	switch *cmd {
+	case cmdSchema:
+		err = writeSchemas(ctx, cmds, cmdLine.Args())
+	case cmdInit:
+		err = initConfigs(ctx, cmds, cmdLine.Args(), defaultCfg)
+	default:
+		execute, ok := cmds[*cmd]
+		if !ok {
+			cmdLine.Usage()
+			l.Fatalf("FAILED: Unknown command: '%s'", *cmd)
+			return
+		}
+		err = errs.Join(execute.exec(ctx, *cfg, overrides, *printCfg), fs.CloseArchives())
+	}
 	if err != nil {
 		l.Fatalf("FAILED: cmd = '%s': %v", *cmd, err)
 		return
 	}
 
-	fmt.Println("DONE")
+	if !*printCfg {
+		fmt.Println("DONE")
+	}
+}
+
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
 }
 
 type execFn[T any] func(ctx context.Context, cfg T) error
-type loadCfgFn[T any] func(cfgPath string) (T, error)
+type loadCfgFn[T any] func(cfgPath string, overrides []misc.ConfigOverride) (T, error)
+
+type command struct {
+	exec   func(ctx context.Context, cfg string, overrides []misc.ConfigOverride, printCfg bool) error
+	config any
+}
+
+func makeExecCmdFn[T any](exec execFn[T], loadCfg loadCfgFn[T]) command {
+	var zero T
+	return command{
+		config: zero,
+		exec: func(ctx context.Context, cfgPath string, overrides []misc.ConfigOverride, printCfg bool) error {
+			cfg, err := loadCfg(cfgPath, overrides)
+			if err != nil {
+				return errs.Errorf("failed to load config: %w", err)
+			}
+			if printCfg {
+				return printConfig(os.Stdout, cfg)
+			}
+			return errs.Wrap(exec(ctx, cfg))
+		},
+	}
+}
+
+type streamFn[T any] func(ctx context.Context, cfg T, in io.Reader, out io.Writer) error
 
-func makeExecCmdFn[T any](exec execFn[T], loadCfg loadCfgFn[T]) func(ctx context.Context, cfg string) error {
-	return func(ctx context.Context, cfgPath string) error {
-		cfg, err := loadCfg(cfgPath)
+func makeStreamCmdFn[T any](stream streamFn[T], loadCfg loadCfgFn[T]) func(ctx context.Context, cfg string, overrides []misc.ConfigOverride, printCfg bool, in io.Reader, out io.Writer) error {
+	return func(ctx context.Context, cfgPath string, overrides []misc.ConfigOverride, printCfg bool, in io.Reader, out io.Writer) error {
+		cfg, err := loadCfg(cfgPath, overrides)
 		if err != nil {
 			return errs.Errorf("failed to load config: %w", err)
 		}
-		return errs.Wrap(exec(ctx, cfg))
+		if printCfg {
+			return printConfig(os.Stderr, cfg)
+		}
+		return errs.Wrap(stream(ctx, cfg, in, out))
+	}
+}
+
+func printConfig(w io.Writer, cfg any) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return errs.Wrap(err)
+}
+
+type configOverridesFlag []misc.ConfigOverride
+
+func (this *configOverridesFlag) String() string {
+	return fmt.Sprintf("%v", []misc.ConfigOverride(*this))
+}
+
+func (this *configOverridesFlag) Set(value string) error {
+	o, err := misc.ParseConfigOverride(value)
+	if err != nil {
+		return err
 	}
+	*this = append(*this, o)
+	return nil
 }
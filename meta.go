@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"irptools/tools/utils"
+	"irptools/utils/alg"
+	"irptools/utils/errs"
+	jsonutils "irptools/utils/json"
+	"irptools/utils/logs"
+	"irptools/utils/misc"
+)
+
+const (
+	cmdSchema = "schema"
+	cmdInit   = "init"
+
+	schemaFolder = "schema"
+)
+
+//go:embed sample/cfg_*.json
+var sampleConfigs embed.FS
+
+func writeSchemas(ctx context.Context, cmds map[string]command, names []string) error {
+	names, err := commandNames(cmds, names)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		filePath, err := writeSchema(name, cmds[name])
+		if err != nil {
+			return errs.Errorf("failed to write schema for '%s': %w", name, err)
+		}
+		logs.L(ctx).I("schema -> %s", filePath)
+	}
+
+	return nil
+}
+
+func writeSchema(name string, cmd command) (string, error) {
+	_, err := os.Stat(schemaFolder)
+	if os.IsNotExist(err) {
+		err = os.MkdirAll(schemaFolder, 0755)
+	}
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+
+	filePath := schemaFilePath(name)
+	err = utils.StoreJson(filePath, jsonutils.NewSchema(name, cmd.config))
+	return filePath, errs.Wrap(err)
+}
+
+func schemaFilePath(name string) string {
+	return filepath.Join(schemaFolder, name+".schema.json")
+}
+
+func initConfigs(ctx context.Context, cmds map[string]command, names []string, cfgTemplate string) error {
+	if len(names) == 0 {
+		return errs.Errorf("expected command names to init configs for: %s", sortedCommandNames(cmds))
+	}
+
+	names, err := commandNames(cmds, names)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		filePath := strings.ReplaceAll(cfgTemplate, "$cmd$", name)
+		if _, err = os.Stat(filePath); err == nil {
+			return errs.Errorf("config already exists: '%s'", filePath)
+		}
+
+		schemaPath, err := writeSchema(name, cmds[name])
+		if err != nil {
+			return errs.Errorf("failed to write schema for '%s': %w", name, err)
+		}
+
+		cfg, err := starterConfig(name, cmds[name])
+		if err != nil {
+			return errs.Errorf("failed to make starter config for '%s': %w", name, err)
+		}
+		cfg[misc.ConfigSchemaKey] = filepath.ToSlash(schemaPath)
+		cfg[misc.ConfigDocKey] = jsonutils.FieldDocs(cmds[name].config)
+
+		err = utils.StoreJson(filePath, cfg)
+		if err != nil {
+			return errs.Errorf("failed to write config for '%s': %w", name, err)
+		}
+		logs.L(ctx).I("config -> %s", filePath)
+	}
+
+	return nil
+}
+
+func starterConfig(name string, cmd command) (map[string]any, error) {
+	data, err := sampleConfigs.ReadFile("sample/cfg_" + name + ".json")
+	if err != nil {
+		data, err = json.Marshal(cmd.config)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	cfg := map[string]any{}
+	err = json.Unmarshal(data, &cfg)
+	return cfg, errs.Wrap(err)
+}
+
+func commandNames(cmds map[string]command, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return sortedCommandNames(cmds), nil
+	}
+	for _, name := range names {
+		if _, ok := cmds[name]; !ok {
+			return nil, errs.Errorf("unknown command: '%s'", name)
+		}
+	}
+	return names, nil
+}
+
+func sortedCommandNames(cmds map[string]command) []string {
+	names := alg.MapKeys(cmds)
+	sort.Strings(names)
+	return names
+}
@@ -2,5 +2,25 @@
 
 export PATH=$PATH:.
 irptools.exe -cmd=parse -cfg=cfg_parse.json
+irptools.exe -cmd=parse -cfg=cfg_parse.json -set target.folder.path=./parsed_zip -set target.layout=zip
+irptools.exe -cmd=compact -cfg=cfg_compact.json
+irptools.exe -cmd=verify -cfg=cfg_verify.json
+irptools.exe -cmd=analyze -cfg=cfg_analyze.json
+irptools.exe -cmd=diff -cfg=cfg_diff.json
+irptools.exe -cmd=merge -cfg=cfg_merge.json
 irptools.exe -cmd=filter -cfg=cfg_filter.json
+irptools.exe -cmd=index -cfg=cfg_index.json
+irptools.exe -cmd=filter -cfg=cfg_filter.json -set source=./catalog/result -set target.folder.path=./filtered_catalog
+irptools.exe -cmd=stat_diff -cfg=cfg_stat_diff.json
 irptools.exe -cmd=export_fz -cfg=cfg_export_fz.json
+irptools.exe -cmd=export_fz -cfg=cfg_export_fz.json -set target.folder.path=./exported_fz_one -set target.toOneFolder=true
+irptools.exe -cmd=export_csv -cfg=cfg_export_csv.json
+irptools.exe -cmd=export_fz_universal -cfg=cfg_export_fz_universal.json
+irptools.exe -cmd=export_template -cfg=cfg_export_template.json
+irptools.exe -cmd=export_template -cfg=cfg_export_template.json -set target.folder.path=./exported_xml -set target.fileName=codes.xml -set template=./templates/vendor.xml.tmpl
+irptools.exe -cmd=plan -cfg=cfg_plan.json
+irptools.exe -cmd=pipeline -cfg=cfg_pipeline.json
+cat data/fz/TVs/LG/LG.ir | irptools.exe -cmd=parse -stream | irptools.exe -cmd=export_csv -stream > stream_LG.csv
+cat data/fz/TVs/LG/LG.ir | irptools.exe -cmd=parse -stream | irptools.exe -cmd=export_pronto -stream > stream_LG.txt
+cat data/fz/TVs/LG/LG.ir | irptools.exe -cmd=parse -stream | irptools.exe -cmd=filter -stream -set 'filter={"function":"Power"}' | irptools.exe -cmd=export_pronto -stream > stream_LG_power.txt
+irptools.exe -cmd=serve -cfg=cfg_serve.json
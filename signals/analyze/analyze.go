@@ -0,0 +1,266 @@
+package analyze
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/utils/errs"
+)
+
+type Options struct {
+	Tolerance    float64 `json:"tolerance" doc:"relative tolerance of duration bins"`
+	MinBinShare  float64 `json:"minBinShare" doc:"min share of a significant bin"`
+	GapUnits     int     `json:"gapUnits" doc:"units a frame gap is at least"`
+	LeaderFactor float64 `json:"leaderFactor" doc:"units a leader mark is at least"`
+}
+
+func (this Options) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckPositive(this.Tolerance, "tolerance")
+		errs.ThrowCheckNotNegative(this.MinBinShare, "minBinShare")
+		errs.ThrowCheckPositive(this.GapUnits, "gapUnits")
+		errs.ThrowCheckPositive(this.LeaderFactor, "leaderFactor")
+	})
+}
+
+func (this Options) Adjust() Options {
+	if this.Tolerance == 0 {
+		this.Tolerance = 0.3
+	}
+	if this.MinBinShare == 0 {
+		this.MinBinShare = 0.05
+	}
+	if this.GapUnits == 0 {
+		this.GapUnits = 10
+	}
+	if this.LeaderFactor == 0 {
+		this.LeaderFactor = 2.5
+	}
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Modulation string
+
+const (
+	ModulationPulseDistance Modulation = "pulse-distance"
+	ModulationPulseWidth    Modulation = "pulse-width"
+	ModulationBiphase       Modulation = "biphase"
+	ModulationUnknown       Modulation = "unknown"
+)
+
+type Analysis struct {
+	Frequency  irp.Frequency `json:"frequency"`
+	Unit       irp.Micros    `json:"unit"`
+	Marks      Histogram     `json:"marks"`
+	Spaces     Histogram     `json:"spaces"`
+	Frames     int           `json:"frames"`
+	Leader     []irp.Micros  `json:"leader,omitempty"`
+	Trailer    irp.Micros    `json:"trailer,omitempty"`
+	Gap        irp.Micros    `json:"gap,omitempty"`
+	Modulation Modulation    `json:"modulation"`
+	Zero       []irp.Micros  `json:"zero,omitempty"`
+	One        []irp.Micros  `json:"one,omitempty"`
+	Bits       string        `json:"bits"`
+}
+
+func Analyze(data irp.SignalData, frequency irp.Frequency, opts Options) Analysis {
+	a := Analysis{Frequency: frequency, Modulation: ModulationUnknown}
+	if len(data) < 4 {
+		return a
+	}
+
+	a.Marks = NewHistogram(everyOther(data, 0), opts.Tolerance)
+	a.Spaces = NewHistogram(everyOther(data, 1), opts.Tolerance)
+
+	all := a.Marks.Merge(a.Spaces, opts.Tolerance).Significant(opts.MinBinShare)
+	if len(all) == 0 {
+		return a
+	}
+	a.Unit = all[0].Center
+
+	gapTime := a.Unit * irp.Micros(opts.GapUnits)
+	a.Frames = len(data.SplitFrames(gapTime))
+
+	frame := data[:data.FirstFrameLength(gapTime, 2)]
+	if len(frame) < len(data) {
+		a.Gap = data[len(frame)]
+	}
+
+	body := frame
+	if len(frame) >= 2 && float64(frame[0]) > float64(a.Unit)*opts.LeaderFactor {
+		a.Leader = []irp.Micros{frame[0], frame[1]}
+		body = frame[2:]
+	}
+
+	bodyMarks := NewHistogram(everyOther(body, 0), opts.Tolerance).Significant(opts.MinBinShare)
+	bodySpaces := NewHistogram(everyOther(body, 1), opts.Tolerance).Significant(opts.MinBinShare)
+
+	switch {
+	case len(bodyMarks) == 1 && len(bodySpaces) == 2:
+		a.analyzePulseDistance(body, bodyMarks, bodySpaces)
+	case len(bodyMarks) == 2 && len(bodySpaces) == 1:
+		a.analyzePulseWidth(body, bodyMarks, bodySpaces)
+	case len(bodyMarks) <= 2 && len(bodySpaces) <= 2:
+		a.analyzeBiphase(body, opts.Tolerance)
+	}
+
+	return a
+}
+
+func (this *Analysis) analyzePulseDistance(body irp.SignalData, marks, spaces Histogram) {
+	bits := strings.Builder{}
+	for i := 0; i+1 < len(body); i += 2 {
+		if spaces.Find(body[i+1]) == 1 {
+			bits.WriteByte('1')
+		} else {
+			bits.WriteByte('0')
+		}
+	}
+	if len(body)%2 == 1 {
+		this.Trailer = body[len(body)-1]
+	}
+
+	this.Modulation = ModulationPulseDistance
+	this.Zero = []irp.Micros{marks[0].Center, spaces[0].Center}
+	this.One = []irp.Micros{marks[0].Center, spaces[1].Center}
+	this.Bits = bits.String()
+}
+
+func (this *Analysis) analyzePulseWidth(body irp.SignalData, marks, spaces Histogram) {
+	bits := strings.Builder{}
+	for i := 0; i < len(body); i += 2 {
+		if marks.Find(body[i]) == 1 {
+			bits.WriteByte('1')
+		} else {
+			bits.WriteByte('0')
+		}
+	}
+
+	this.Modulation = ModulationPulseWidth
+	this.Zero = []irp.Micros{marks[0].Center, spaces[0].Center}
+	this.One = []irp.Micros{marks[1].Center, spaces[0].Center}
+	this.Bits = bits.String()
+}
+
+func (this *Analysis) analyzeBiphase(body irp.SignalData, tolerance float64) {
+	// expand durations to half-bit levels, true is mark
+	levels := make([]bool, 0, len(body)*2)
+	for i, d := range body {
+		units := math.Round(float64(d) / float64(this.Unit))
+		if units < 1 || units > 2 || math.Abs(float64(d)-units*float64(this.Unit)) > units*float64(this.Unit)*tolerance {
+			return
+		}
+		for j := 0; j < int(units); j++ {
+			levels = append(levels, i%2 == 0)
+		}
+	}
+
+	if len(levels) != 0 && levels[0] {
+		levels = append([]bool{false}, levels...)
+	}
+	if len(levels)%2 == 1 {
+		levels = append(levels, false)
+	}
+
+	bits := strings.Builder{}
+	for i := 0; i < len(levels); i += 2 {
+		switch {
+		case !levels[i] && levels[i+1]:
+			bits.WriteByte('1')
+		case levels[i] && !levels[i+1]:
+			bits.WriteByte('0')
+		default:
+			return
+		}
+	}
+
+	this.Modulation = ModulationBiphase
+	this.Zero = []irp.Micros{this.Unit, this.Unit}
+	this.One = []irp.Micros{this.Unit, this.Unit}
+	this.Bits = bits.String()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this Analysis) Units(d irp.Micros) int {
+	if this.Unit == 0 {
+		return 0
+	}
+	return int(math.Round(float64(d) / float64(this.Unit)))
+}
+
+func (this Analysis) Irp() string {
+	if this.Modulation == ModulationUnknown {
+		return ""
+	}
+
+	b := strings.Builder{}
+	_, _ = fmt.Fprintf(&b, "{%.1fk,%d}", float64(this.Frequency)/1000, this.Unit)
+
+	switch this.Modulation {
+	case ModulationBiphase:
+		b.WriteString("<1,-1|-1,1>")
+	default:
+		_, _ = fmt.Fprintf(&b, "<%d,-%d|%d,-%d>",
+			this.Units(this.Zero[0]), this.Units(this.Zero[1]), this.Units(this.One[0]), this.Units(this.One[1]))
+	}
+
+	parts := make([]string, 0, 5)
+	if len(this.Leader) != 0 {
+		parts = append(parts, fmt.Sprintf("%d,-%d", this.Units(this.Leader[0]), this.Units(this.Leader[1])))
+	}
+	parts = append(parts, fmt.Sprintf("F:%d", len(this.Bits)))
+	if this.Trailer != 0 {
+		parts = append(parts, fmt.Sprintf("%d", this.Units(this.Trailer)))
+	}
+	if this.Gap != 0 {
+		parts = append(parts, fmt.Sprintf("-%dm", int(math.Round(float64(this.Gap)/1000))))
+	}
+	_, _ = fmt.Fprintf(&b, "(%s)", strings.Join(parts, ","))
+
+	if this.Frames > 1 {
+		b.WriteByte('*')
+	}
+
+	return b.String()
+}
+
+func (this Analysis) Key() string {
+	leader := "-"
+	if len(this.Leader) != 0 {
+		leader = fmt.Sprintf("%d,%d", this.Units(this.Leader[0]), this.Units(this.Leader[1]))
+	}
+
+	shape := "-"
+	if len(this.Zero) != 0 {
+		shape = fmt.Sprintf("%d,%d|%d,%d", this.Units(this.Zero[0]), this.Units(this.Zero[1]), this.Units(this.One[0]), this.Units(this.One[1]))
+	}
+
+	return fmt.Sprintf("%s:%dk:%d:%s:%s", this.Modulation, (this.Frequency+500)/1000, len(this.Bits), leader, shape)
+}
+
+func BitsToHex(bits string) string {
+	bytes := make([]string, 0, (len(bits)+7)/8)
+	for i := 0; i < len(bits); i += 8 {
+		value := 0
+		for j := 0; j < 8 && i+j < len(bits); j++ {
+			if bits[i+j] == '1' {
+				value |= 1 << j
+			}
+		}
+		bytes = append(bytes, fmt.Sprintf("%02X", value))
+	}
+	return strings.Join(bytes, " ")
+}
+
+func everyOther(data irp.SignalData, offset int) []irp.Micros {
+	result := make([]irp.Micros, 0, len(data)/2+1)
+	for i := offset; i < len(data); i += 2 {
+		result = append(result, data[i])
+	}
+	return result
+}
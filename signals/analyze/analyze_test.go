@@ -0,0 +1,46 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/irp"
+)
+
+func Test_Analyze_Protocols(t *testing.T) {
+	tests := []struct {
+		protocol   string
+		code       irp.SignalCode
+		modulation Modulation
+		hex        string
+	}{
+		{"nec", irp.SignalCode{Address: [4]uint8{0x04}, Command: [4]uint8{0x08}}, ModulationPulseDistance, "04 FB 08 F7"},
+		{"samsung32", irp.SignalCode{Address: [4]uint8{0x07}, Command: [4]uint8{0x02}}, ModulationPulseDistance, "07 07 02 FD"},
+		{"sirc", irp.SignalCode{Address: [4]uint8{0x01}, Command: [4]uint8{0x15}}, ModulationPulseWidth, "95 00"},
+		{"rc5", irp.SignalCode{Address: [4]uint8{0x05}, Command: [4]uint8{0x0C}}, ModulationBiphase, ""},
+	}
+
+	opts := Options{}.Adjust()
+	for _, test := range tests {
+		p, err := irp.GetIrp(test.protocol)
+		assert.NoError(t, err)
+		data, err := p.Decode(test.code)
+		assert.NoError(t, err)
+
+		a := Analyze(data, p.Frequency(), opts)
+		assert.Equal(t, test.modulation, a.Modulation, test.protocol)
+		if test.hex != "" {
+			assert.Equal(t, test.hex, BitsToHex(a.Bits), test.protocol)
+		}
+		assert.NotEmpty(t, a.Irp(), test.protocol)
+	}
+}
+
+func Test_Analyze_Histogram(t *testing.T) {
+	h := NewHistogram([]irp.Micros{560, 1690, 540, 580, 1700, 9000}, 0.2)
+	assert.Len(t, h, 3)
+	assert.Equal(t, 3, h[0].Count)
+	assert.Equal(t, irp.Micros(560), h[0].Center)
+	assert.Equal(t, 1, h.Find(1650))
+}
@@ -0,0 +1,121 @@
+package analyze
+
+import (
+	"slices"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+)
+
+type Group struct {
+	Key        string        `json:"key"`
+	Irp        string        `json:"irp"`
+	Modulation Modulation    `json:"modulation"`
+	Frequency  irp.Frequency `json:"frequency"`
+	Unit       irp.Micros    `json:"unit"`
+	BitsCount  int           `json:"bitsCount"`
+	Constant   string        `json:"constant"`
+	Marks      Histogram     `json:"marks"`
+	Spaces     Histogram     `json:"spaces"`
+	Signals    []GroupSignal `json:"signals"`
+	analysis   Analysis
+	units      []irp.Micros
+}
+
+type GroupSignal struct {
+	Id       string `json:"id"`
+	Source   string `json:"source"`
+	Brand    string `json:"brand"`
+	Device   string `json:"device"`
+	Function string `json:"function"`
+	Bits     string `json:"bits"`
+	Hex      string `json:"hex"`
+}
+
+type Analyzer struct {
+	opts   Options
+	groups map[string]*Group
+	keys   []string
+}
+
+func NewAnalyzer(opts Options) *Analyzer {
+	return &Analyzer{
+		opts:   opts,
+		groups: map[string]*Group{},
+	}
+}
+
+func (this *Analyzer) Consume(s signal.Signal) error {
+	a := Analyze(s.Data, s.Frequency, this.opts)
+	key := a.Key()
+
+	g, ok := this.groups[key]
+	if !ok {
+		g = &Group{
+			Key:        key,
+			Modulation: a.Modulation,
+			Frequency:  a.Frequency,
+			BitsCount:  len(a.Bits),
+			Constant:   a.Bits,
+			analysis:   a,
+		}
+		this.groups[key] = g
+		this.keys = append(this.keys, key)
+	}
+
+	g.Marks = g.Marks.Merge(a.Marks, this.opts.Tolerance)
+	g.Spaces = g.Spaces.Merge(a.Spaces, this.opts.Tolerance)
+	g.units = append(g.units, a.Unit)
+	g.Constant = constantBits(g.Constant, a.Bits)
+	g.Signals = append(g.Signals, GroupSignal{
+		Id:       s.Id,
+		Source:   s.Source,
+		Brand:    s.Brand,
+		Device:   s.Device,
+		Function: s.Function,
+		Bits:     a.Bits,
+		Hex:      BitsToHex(a.Bits),
+	})
+
+	return nil
+}
+
+func (this *Analyzer) Groups() []Group {
+	groups := make([]Group, 0, len(this.keys))
+	for _, key := range this.keys {
+		g := *this.groups[key]
+		g.Unit = median(g.units)
+		g.analysis.Unit = g.Unit
+		g.Irp = g.analysis.Irp()
+		groups = append(groups, g)
+	}
+
+	slices.SortStableFunc(groups, func(a, b Group) int {
+		return len(b.Signals) - len(a.Signals)
+	})
+
+	return groups
+}
+
+func constantBits(constant string, bits string) string {
+	if len(constant) != len(bits) {
+		return ""
+	}
+
+	result := []byte(constant)
+	for i := range result {
+		if result[i] != bits[i] {
+			result[i] = '.'
+		}
+	}
+	return string(result)
+}
+
+func median(values []irp.Micros) irp.Micros {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,99 @@
+package analyze
+
+import (
+	"slices"
+
+	"irptools/signals/irp"
+)
+
+type Bin struct {
+	Center irp.Micros `json:"center"`
+	Min    irp.Micros `json:"min"`
+	Max    irp.Micros `json:"max"`
+	Count  int        `json:"count"`
+}
+
+type Histogram []Bin
+
+func NewHistogram(durations []irp.Micros, tolerance float64) Histogram {
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	h := Histogram{}
+	sum := irp.Micros(0)
+	for _, d := range sorted {
+		if len(h) != 0 {
+			last := &h[len(h)-1]
+			if float64(d-last.Min) <= float64(last.Min)*tolerance {
+				sum += d
+				last.Max = d
+				last.Count++
+				last.Center = sum / irp.Micros(last.Count)
+				continue
+			}
+		}
+		sum = d
+		h = append(h, Bin{Center: d, Min: d, Max: d, Count: 1})
+	}
+
+	return h
+}
+
+func (this Histogram) Find(d irp.Micros) int {
+	best := -1
+	bestDiff := irp.Micros(0)
+	for i, bin := range this {
+		diff := absDiff(bin.Center, d)
+		if best < 0 || diff < bestDiff {
+			best, bestDiff = i, diff
+		}
+	}
+	return best
+}
+
+func (this Histogram) Significant(minShare float64) Histogram {
+	total := 0
+	for _, bin := range this {
+		total += bin.Count
+	}
+
+	result := Histogram{}
+	for _, bin := range this {
+		if float64(bin.Count) >= float64(total)*minShare {
+			result = append(result, bin)
+		}
+	}
+	return result
+}
+
+func (this Histogram) Merge(other Histogram, tolerance float64) Histogram {
+	bins := append(slices.Clone(this), other...)
+	slices.SortFunc(bins, func(a, b Bin) int {
+		return int(a.Center) - int(b.Center)
+	})
+
+	h := Histogram{}
+	for _, bin := range bins {
+		if len(h) != 0 {
+			last := &h[len(h)-1]
+			if float64(bin.Center-last.Center) <= float64(last.Center)*tolerance {
+				total := last.Count + bin.Count
+				last.Center = (last.Center*irp.Micros(last.Count) + bin.Center*irp.Micros(bin.Count)) / irp.Micros(total)
+				last.Min = min(last.Min, bin.Min)
+				last.Max = max(last.Max, bin.Max)
+				last.Count = total
+				continue
+			}
+		}
+		h = append(h, bin)
+	}
+
+	return h
+}
+
+func absDiff(a, b irp.Micros) irp.Micros {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,319 @@
+package catalog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+)
+
+const (
+	DataFileName  = signalutils.CatalogDataFileName
+	IndexFileName = signalutils.CatalogIndexFileName
+)
+
+const (
+	FieldBrand       = "brand"
+	FieldDevice      = "device"
+	FieldFunction    = "function"
+	FieldProtocol    = "protocol"
+	FieldFrequency   = "frequency"
+	FieldFingerprint = "fingerprint"
+)
+
+var IndexedFields = []string{FieldBrand, FieldDevice, FieldFunction, FieldProtocol, FieldFrequency, FieldFingerprint}
+
+type Record = signalutils.SignalRecord
+
+type Index struct {
+	Count  int                           `json:"count"`
+	Size   int64                         `json:"size"`
+	Files  map[string]int                `json:"files"`
+	Fields map[string]map[string][]int64 `json:"fields"`
+}
+
+func newIndex() Index {
+	idx := Index{Files: map[string]int{}, Fields: map[string]map[string][]int64{}}
+	for _, field := range IndexedFields {
+		idx.Fields[field] = map[string][]int64{}
+	}
+	return idx
+}
+
+func (this *Index) add(offset int64, s signal.Signal) {
+	for _, field := range IndexedFields {
+		key := indexKey(fieldValue(s, field))
+		this.Fields[field][key] = append(this.Fields[field][key], offset)
+	}
+	this.Count++
+}
+
+func fieldValue(s signal.Signal, field string) any {
+	switch field {
+	case FieldBrand:
+		return s.Brand
+	case FieldDevice:
+		return s.Device
+	case FieldFunction:
+		return s.Function
+	case FieldProtocol:
+		return s.Protocol
+	case FieldFrequency:
+		return s.Frequency
+	case FieldFingerprint:
+		return signal.FingerprintOf(s)
+	}
+	return nil
+}
+
+func indexKey(value any) string {
+	return fmt.Sprintf("%v", value)
+}
+
+func Exists(dirPath string) bool {
+	return signalutils.IsCatalog(dirPath)
+}
+
+func loadIndex(dirPath string) (Index, error) {
+	data, err := os.ReadFile(filepath.Join(dirPath, IndexFileName))
+	if err != nil {
+		return Index{}, errs.Wrap(err)
+	}
+
+	idx := newIndex()
+	err = json.Unmarshal(data, &idx)
+	if err != nil {
+		return Index{}, errs.Errorf("failed to decode index: %w", err)
+	}
+	if idx.Files == nil {
+		idx.Files = map[string]int{}
+	}
+	for _, field := range IndexedFields {
+		if idx.Fields[field] == nil {
+			idx.Fields[field] = map[string][]int64{}
+		}
+	}
+	return idx, nil
+}
+
+func storeIndex(dirPath string, idx Index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(os.WriteFile(filepath.Join(dirPath, IndexFileName), data, 0644))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func OpenWriter(dirPath string) (*Writer, error) {
+	_, err := fs.EnsureDirExists(dirPath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	idx := newIndex()
+	if Exists(dirPath) {
+		idx, err = loadIndex(dirPath)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	file, err := os.OpenFile(filepath.Join(dirPath, DataFileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	size, err := fs.GetFileSize(file)
+	if err != nil {
+		return nil, errs.Join(errs.Wrap(err), file.Close())
+	}
+	if int64(size) != idx.Size {
+		return nil, errs.Join(errs.Errorf("index is out of date: data size %v, indexed %v", size, idx.Size), file.Close())
+	}
+
+	return &Writer{dirPath: dirPath, file: file, buf: bufio.NewWriter(file), idx: idx}, nil
+}
+
+type Writer struct {
+	dirPath  string
+	file     *os.File
+	buf      *bufio.Writer
+	idx      Index
+	lastFile string
+}
+
+func (this *Writer) Append(file string, s signal.Signal) error {
+	file = filepath.ToSlash(file)
+	if file != this.lastFile {
+		if _, ok := this.idx.Files[file]; ok {
+			return errs.Errorf("file is already in catalog: '%s'", file)
+		}
+		this.lastFile = file
+	}
+
+	data, err := json.Marshal(Record{File: file, Signal: s})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	data = append(data, '\n')
+
+	_, err = this.buf.Write(data)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	this.idx.add(this.idx.Size, s)
+	this.idx.Files[file]++
+	this.idx.Size += int64(len(data))
+	return nil
+}
+
+func (this *Writer) Count() int {
+	return this.idx.Count
+}
+
+func (this *Writer) Close() error {
+	err := errs.Join(this.buf.Flush(), this.file.Close())
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return storeIndex(this.dirPath, this.idx)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func Open(dirPath string) (*Catalog, error) {
+	idx, err := loadIndex(dirPath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	file, err := os.Open(filepath.Join(dirPath, DataFileName))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return &Catalog{file: file, idx: idx}, nil
+}
+
+type Catalog struct {
+	file *os.File
+	idx  Index
+}
+
+func (this *Catalog) Count() int {
+	return this.idx.Count
+}
+
+func (this *Catalog) Lookup(field string, values []any) ([]int64, bool) {
+	index, ok := this.idx.Fields[field]
+	if !ok {
+		return nil, false
+	}
+
+	offsets := []int64{}
+	for _, value := range values {
+		offsets = append(offsets, index[indexKey(value)]...)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, true
+}
+
+func (this *Catalog) Enum(ctx context.Context, offsets []int64, consume func(Record) error) error {
+	if offsets == nil {
+		return this.enumAll(ctx, consume)
+	}
+
+	for _, offset := range offsets {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		r := Record{}
+		err := json.NewDecoder(io.NewSectionReader(this.file, offset, this.idx.Size-offset)).Decode(&r)
+		if err != nil {
+			return errs.Errorf("failed to decode record at %v: %w", offset, err)
+		}
+
+		err = consume(r)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+func (this *Catalog) enumAll(ctx context.Context, consume func(Record) error) error {
+	decoder := json.NewDecoder(io.NewSectionReader(this.file, 0, this.idx.Size))
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		r := Record{}
+		err := decoder.Decode(&r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		err = consume(r)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+	}
+}
+
+func (this *Catalog) EnumSignals(ctx context.Context, offsets []int64, rootPath string, getConsumer signalutils.SignalsToFileConsumerSourceFn) (err error) {
+	var consumer signalutils.ClosableSignalConsumer
+	file := ""
+	seen := map[string]struct{}{}
+	defer func() {
+		if consumer != nil {
+			err = errs.Join(err, consumer.Close())
+		}
+	}()
+
+	return this.Enum(ctx, offsets, func(r Record) error {
+		if consumer == nil || r.File != file {
+			if consumer != nil {
+				err := consumer.Close()
+				consumer = nil
+				if err != nil {
+					return errs.Wrap(err)
+				}
+			}
+
+			if _, ok := seen[r.File]; ok {
+				return errs.Errorf("records of '%s' are not contiguous", r.File)
+			}
+			seen[r.File] = struct{}{}
+
+			var err error
+			file = r.File
+			consumer, err = getConsumer(filepath.Join(rootPath, filepath.FromSlash(r.File)))
+			if err != nil {
+				return errs.Wrap(err)
+			}
+		}
+		return consumer.Consume(r.Signal)
+	})
+}
+
+func (this *Catalog) Close() error {
+	return this.file.Close()
+}
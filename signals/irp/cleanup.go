@@ -0,0 +1,77 @@
+package irp
+
+const DefaultTrailingSilence Micros = 10000
+
+func (this SignalData) SplitFrames(splitTime Micros) []SignalData {
+	frames := make([]SignalData, 0, 1)
+	if len(this) == 0 {
+		return frames
+	}
+
+	begin := 0
+	for i := 1; i < len(this); i += 2 {
+		if this[i] > splitTime {
+			frame := this[begin : i+1]
+			frames = append(frames, frame.Clone())
+			begin = i + 1
+		}
+	}
+
+	if begin < len(this) {
+		frame := this[begin:]
+		frames = append(frames, frame.Clone())
+	}
+
+	return frames
+}
+
+func (this SignalData) KeepFrames(splitTime Micros, count int) SignalData {
+	frames := this.SplitFrames(splitTime)
+	if count <= 0 || count >= len(frames) {
+		return this.Clone()
+	}
+
+	result := NewSignalData()
+	for _, frame := range frames[:count] {
+		result.Add(frame...)
+	}
+	return result
+}
+
+func (this SignalData) DropZeroDurations() SignalData {
+	result := make(SignalData, 0, len(this))
+
+	i := 0
+	for i < len(this) && this[i] == 0 {
+		i += 2 // zero mark makes the next space a leading silence
+	}
+
+	for ; i < len(this); i++ {
+		if this[i] != 0 {
+			result = append(result, this[i])
+			continue
+		}
+
+		if i+1 < len(this) && len(result) > 0 {
+			result[len(result)-1] += this[i+1]
+			i++
+		}
+	}
+
+	return result
+}
+
+func (this SignalData) WithTrailingSilence(silence Micros) SignalData {
+	result := this.Clone()
+	result.WithPauseTie(silence)
+	result[len(result)-1] = silence
+	return result
+}
+
+func (this SignalData) WithEvenLength() SignalData {
+	result := this.Clone()
+	if len(result)%2 == 1 {
+		result.Add(DefaultTrailingSilence)
+	}
+	return result
+}
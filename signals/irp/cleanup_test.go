@@ -0,0 +1,52 @@
+package irp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Cleanup_SplitFrames(t *testing.T) {
+	assert.Equal(t, []SignalData{}, SignalData{}.SplitFrames(1000))
+	assert.Equal(t, []SignalData{{100, 200, 300}}, SignalData{100, 200, 300}.SplitFrames(1000))
+	assert.Equal(t, []SignalData{{100, 2000}, {300}}, SignalData{100, 2000, 300}.SplitFrames(1000))
+	assert.Equal(t, []SignalData{{100, 200, 300, 5000}, {100, 200, 300, 5000}},
+		SignalData{100, 200, 300, 5000, 100, 200, 300, 5000}.SplitFrames(1000))
+
+	// marks are never used as split points
+	assert.Equal(t, []SignalData{{5000, 200, 300}}, SignalData{5000, 200, 300}.SplitFrames(1000))
+}
+
+func Test_Cleanup_KeepFrames(t *testing.T) {
+	data := SignalData{100, 200, 300, 5000, 110, 210, 310, 6000, 120}
+	assert.Equal(t, SignalData{100, 200, 300, 5000}, data.KeepFrames(1000, 1))
+	assert.Equal(t, SignalData{100, 200, 300, 5000, 110, 210, 310, 6000}, data.KeepFrames(1000, 2))
+	assert.Equal(t, data, data.KeepFrames(1000, 3))
+	assert.Equal(t, data, data.KeepFrames(1000, 0))
+}
+
+func Test_Cleanup_DropZeroDurations(t *testing.T) {
+	assert.Equal(t, SignalData{100, 200, 300}, SignalData{100, 200, 300}.DropZeroDurations())
+	assert.Equal(t, SignalData{100, 500}, SignalData{100, 200, 0, 300}.DropZeroDurations())
+	assert.Equal(t, SignalData{100, 200}, SignalData{0, 50, 100, 200}.DropZeroDurations())
+	assert.Equal(t, SignalData{100}, SignalData{100, 0}.DropZeroDurations())
+	assert.NoError(t, ValidateDurations(SignalData{0, 50, 100, 200, 0, 300}.DropZeroDurations()))
+}
+
+func Test_Cleanup_WithTrailingSilence(t *testing.T) {
+	assert.Equal(t, SignalData{100, 200, 300, 10000}, SignalData{100, 200, 300}.WithTrailingSilence(10000))
+	assert.Equal(t, SignalData{100, 10000}, SignalData{100, 200000}.WithTrailingSilence(10000))
+	assert.Equal(t, SignalData{100, 10000}, SignalData{100, 20}.WithTrailingSilence(10000))
+}
+
+func Test_Cleanup_ValidateDurations(t *testing.T) {
+	assert.NoError(t, ValidateDurations(SignalData{100, 200}))
+	assert.Error(t, ValidateDurations(SignalData{}))
+	assert.Error(t, ValidateDurations(SignalData{100}))
+	assert.Error(t, ValidateDurations(SignalData{100, 0}))
+}
+
+func Test_Cleanup_WithEvenLength(t *testing.T) {
+	assert.Equal(t, SignalData{100, 200, 300, DefaultTrailingSilence}, SignalData{100, 200, 300}.WithEvenLength())
+	assert.Equal(t, SignalData{100, 200}, SignalData{100, 200}.WithEvenLength())
+}
@@ -0,0 +1,126 @@
+package irp
+
+import "strings"
+
+type Tolerances struct {
+	PreambleLength int
+	Preamble       Micros
+	Bit            Micros
+}
+
+var supportedTolerances = map[string]Tolerances{
+	"kaseikyo":  {PreambleLength: 2, Preamble: KASEIKYO_PREAMBLE_TOLERANCE, Bit: KASEIKYO_BIT_TOLERANCE},
+	"nec":       {PreambleLength: 2, Preamble: NEC_PREAMBLE_TOLERANCE, Bit: NEC_BIT_TOLERANCE},
+	"necext":    {PreambleLength: 2, Preamble: NEC_PREAMBLE_TOLERANCE, Bit: NEC_BIT_TOLERANCE},
+	"nec42":     {PreambleLength: 2, Preamble: NEC_PREAMBLE_TOLERANCE, Bit: NEC_BIT_TOLERANCE},
+	"rc5":       {PreambleLength: 0, Preamble: RC5_PREAMBLE_TOLERANCE, Bit: RC5_BIT_TOLERANCE},
+	"rc5x":      {PreambleLength: 0, Preamble: RC5_PREAMBLE_TOLERANCE, Bit: RC5_BIT_TOLERANCE},
+	"rc6":       {PreambleLength: 2, Preamble: RC6_PREAMBLE_TOLERANCE, Bit: RC6_BIT_TOLERANCE},
+	"rca":       {PreambleLength: 2, Preamble: RCA_PREAMBLE_TOLERANCE, Bit: RCA_BIT_TOLERANCE},
+	"samsung32": {PreambleLength: 2, Preamble: SAMSUNG_PREAMBLE_TOLERANCE, Bit: SAMSUNG_BIT_TOLERANCE},
+	"sirc":      {PreambleLength: 2, Preamble: SIRC_PREAMBLE_TOLERANCE, Bit: SIRC_BIT_TOLERANCE},
+	"sirc12":    {PreambleLength: 2, Preamble: SIRC_PREAMBLE_TOLERANCE, Bit: SIRC_BIT_TOLERANCE},
+	"sirc15":    {PreambleLength: 2, Preamble: SIRC_PREAMBLE_TOLERANCE, Bit: SIRC_BIT_TOLERANCE},
+	"sirc20":    {PreambleLength: 2, Preamble: SIRC_PREAMBLE_TOLERANCE, Bit: SIRC_BIT_TOLERANCE},
+}
+
+var defaultTolerances = Tolerances{PreambleLength: 2, Preamble: 200, Bit: 120}
+
+func GetTolerances(protocol string) Tolerances {
+	tolerances, ok := supportedTolerances[strings.ToLower(protocol)]
+	if !ok {
+		return defaultTolerances
+	}
+	return tolerances
+}
+
+func (this Tolerances) At(idx int) Micros {
+	if idx < this.PreambleLength {
+		return this.Preamble
+	}
+	return this.Bit
+}
+
+func (this Tolerances) Scaled(scale float64) Tolerances {
+	this.Preamble = Micros(float64(this.Preamble) * scale)
+	this.Bit = Micros(float64(this.Bit) * scale)
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Deviation struct {
+	Index     int    `json:"index"`
+	Expected  Micros `json:"expected"`
+	Actual    Micros `json:"actual"`
+	Tolerance Micros `json:"tolerance"`
+}
+
+type Comparison struct {
+	Compared      int         `json:"compared"`
+	Matched       int         `json:"matched"`
+	MaxDeviation  Micros      `json:"maxDeviation"`
+	MeanDeviation float64     `json:"meanDeviation"`
+	Deviations    []Deviation `json:"deviations,omitempty"`
+}
+
+func (this Comparison) Confidence() float64 {
+	if this.Compared == 0 {
+		return 0
+	}
+	return float64(this.Matched) / float64(this.Compared)
+}
+
+func CompareSignalData(expected, actual SignalData, count int, tolerances Tolerances) Comparison {
+	if count <= 0 || count > len(expected) {
+		count = len(expected)
+	}
+
+	result := Comparison{Compared: count}
+	total := Micros(0)
+	for i := 0; i < count; i++ {
+		tolerance := tolerances.At(i)
+		if i >= len(actual) {
+			result.Deviations = append(result.Deviations, Deviation{Index: i, Expected: expected[i], Tolerance: tolerance})
+			continue
+		}
+
+		deviation := absDiff(expected[i], actual[i])
+		total += deviation
+		if deviation > result.MaxDeviation {
+			result.MaxDeviation = deviation
+		}
+
+		if deviation <= tolerance {
+			result.Matched++
+		} else {
+			result.Deviations = append(result.Deviations, Deviation{Index: i, Expected: expected[i], Actual: actual[i], Tolerance: tolerance})
+		}
+	}
+
+	if count > 0 {
+		result.MeanDeviation = float64(total) / float64(count)
+	}
+
+	return result
+}
+
+func absDiff(a, b Micros) Micros {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func CompareFirstFrame(expected, actual SignalData, splitTime Micros, tolerances Tolerances) Comparison {
+	return CompareSignalData(expected, actual, expected.FirstFrameLength(splitTime, tolerances.PreambleLength), tolerances)
+}
+
+func (this SignalData) FirstFrameLength(splitTime Micros, skip int) int {
+	for i := 1; i < len(this); i += 2 {
+		if i >= skip && this[i] > splitTime {
+			return i
+		}
+	}
+	return len(this)
+}
@@ -0,0 +1,29 @@
+package irp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Compare_SignalData(t *testing.T) {
+	tolerances := Tolerances{PreambleLength: 2, Preamble: 200, Bit: 100}
+	expected := SignalData{9000, 4500, 560, 560, 560}
+
+	cmp := CompareSignalData(expected, SignalData{9100, 4400, 600, 700, 560}, 0, tolerances)
+	assert.Equal(t, 5, cmp.Compared)
+	assert.Equal(t, 4, cmp.Matched)
+	assert.Equal(t, Micros(140), cmp.MaxDeviation)
+	assert.Equal(t, []Deviation{{Index: 3, Expected: 560, Actual: 700, Tolerance: 100}}, cmp.Deviations)
+
+	cmp = CompareSignalData(expected, SignalData{9000, 4500}, 3, tolerances)
+	assert.Equal(t, 3, cmp.Compared)
+	assert.Equal(t, 2, cmp.Matched)
+}
+
+func Test_Compare_FirstFrameLength(t *testing.T) {
+	data := SignalData{9000, 4500, 560, 1690, 560, 40000, 9000, 2250, 560}
+	assert.Equal(t, 5, data.FirstFrameLength(4000, 2))
+	assert.Equal(t, 1, data.FirstFrameLength(4000, 0))
+	assert.Equal(t, len(data), data.FirstFrameLength(50000, 2))
+}
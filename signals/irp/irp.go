@@ -21,6 +21,8 @@ var supportedIrpCreators = map[string]func(protocol string) Irp{
 type Irp interface {
 	Protocol() string
 	Frequency() Frequency
+	RepeatPeriod() Micros
+	MinSplitTime() Micros
 	Decode(code SignalCode) (SignalData, error)
 }
 
@@ -43,9 +45,11 @@ func createSupportedIrps() map[string]Irp {
 }
 
 type irpImpl struct {
-	protocol  string
-	frequency Frequency
-	decode    func(code SignalCode) (SignalData, error)
+	protocol     string
+	frequency    Frequency
+	repeatPeriod Micros
+	minSplitTime Micros
+	decode       func(code SignalCode) (SignalData, error)
 }
 
 func (this *irpImpl) Protocol() string {
@@ -56,6 +60,14 @@ func (this *irpImpl) Frequency() Frequency {
 	return this.frequency
 }
 
+func (this *irpImpl) RepeatPeriod() Micros {
+	return this.repeatPeriod
+}
+
+func (this *irpImpl) MinSplitTime() Micros {
+	return this.minSplitTime
+}
+
 func (this *irpImpl) Decode(code SignalCode) (SignalData, error) {
 	return this.decode(code)
 }
@@ -6,9 +6,11 @@ const (
 
 func NewIrpKaseikyo(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyKaseikyo,
-		decode:    DecodeKaseikyo,
+		protocol:     protocol,
+		frequency:    FrequencyKaseikyo,
+		repeatPeriod: KASEIKYO_REPEAT_PERIOD,
+		minSplitTime: KASEIKYO_MIN_SPLIT_TIME,
+		decode:       DecodeKaseikyo,
 	}
 }
 
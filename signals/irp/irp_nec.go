@@ -8,25 +8,31 @@ const (
 
 func NewIrpNec(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyNec,
-		decode:    GetNecDecoder(1),
+		protocol:     protocol,
+		frequency:    FrequencyNec,
+		repeatPeriod: NEC_REPEAT_PERIOD,
+		minSplitTime: NEC_MIN_SPLIT_TIME,
+		decode:       GetNecDecoder(1),
 	}
 }
 
 func NewIrpNecExt(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyNecExt,
-		decode:    GetNecExtDecoder(1),
+		protocol:     protocol,
+		frequency:    FrequencyNecExt,
+		repeatPeriod: NEC_REPEAT_PERIOD,
+		minSplitTime: NEC_MIN_SPLIT_TIME,
+		decode:       GetNecExtDecoder(1),
 	}
 }
 
 func NewIrpNec42(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyNec42,
-		decode:    GetNecExtDecoder(1),
+		protocol:     protocol,
+		frequency:    FrequencyNec42,
+		repeatPeriod: NEC_REPEAT_PERIOD,
+		minSplitTime: NEC_MIN_SPLIT_TIME,
+		decode:       GetNecExtDecoder(1),
 	}
 }
 
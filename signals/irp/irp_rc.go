@@ -8,25 +8,31 @@ const (
 
 func NewIrpRc5(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyRc5,
-		decode:    DecodeRc5,
+		protocol:     protocol,
+		frequency:    FrequencyRc5,
+		repeatPeriod: RC5_REPEAT_PERIOD,
+		minSplitTime: RC5_MIN_SPLIT_TIME,
+		decode:       DecodeRc5,
 	}
 }
 
 func NewIrpRc5x(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyRc5x,
-		decode:    DecodeRc5x,
+		protocol:     protocol,
+		frequency:    FrequencyRc5x,
+		repeatPeriod: RC5_REPEAT_PERIOD,
+		minSplitTime: RC5_MIN_SPLIT_TIME,
+		decode:       DecodeRc5x,
 	}
 }
 
 func NewIrpRc6(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyRc6,
-		decode:    DecodeRc6,
+		protocol:     protocol,
+		frequency:    FrequencyRc6,
+		repeatPeriod: RC6_REPEAT_PERIOD,
+		minSplitTime: RC6_MIN_SPLIT_TIME,
+		decode:       DecodeRc6,
 	}
 }
 
@@ -142,6 +148,7 @@ const (
 	RC5_BIT_TOLERANCE      = 120       // us
 	RC5_SILENCE            = 2700 * 10 // protocol allows 2700 silence, but it is hard to send 1 message without repeat */
 	RC5_MIN_SPLIT_TIME     = 2700
+	RC5_REPEAT_PERIOD      = RC5_BIT * 2 * 64 // 113.7 ms
 )
 
 /***************************************************************************************************
@@ -178,4 +185,5 @@ const (
 	RC6_BIT_TOLERANCE      = 120
 	RC6_SILENCE            = 2700 * 10 // protocol allows 2700 silence, but it is hard to send 1 message without repeat
 	RC6_MIN_SPLIT_TIME     = 2700
+	RC6_REPEAT_PERIOD      = 106700
 )
@@ -6,9 +6,11 @@ const (
 
 func NewIrpRca(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencyRca,
-		decode:    DecodeRca,
+		protocol:     protocol,
+		frequency:    FrequencyRca,
+		repeatPeriod: RCA_SIGNAL_DUR,
+		minSplitTime: RCA_MIN_SPLIT_TIME,
+		decode:       DecodeRca,
 	}
 }
 
@@ -39,11 +41,14 @@ func DecodeRca(code SignalCode) (SignalData, error) {
  */
 
 const (
-	RCA_PREAMBLE_MARK  = 4000
-	RCA_PREAMBLE_SPACE = 4000
-	RCA_BIT1_MARK      = 500
-	RCA_BIT1_SPACE     = 2000
-	RCA_BIT0_MARK      = 500
-	RCA_BIT0_SPACE     = 1000
-	RCA_SIGNAL_DUR     = 64000
+	RCA_PREAMBLE_MARK      = 4000
+	RCA_PREAMBLE_SPACE     = 4000
+	RCA_BIT1_MARK          = 500
+	RCA_BIT1_SPACE         = 2000
+	RCA_BIT0_MARK          = 500
+	RCA_BIT0_SPACE         = 1000
+	RCA_SIGNAL_DUR         = 64000
+	RCA_MIN_SPLIT_TIME     = RCA_PREAMBLE_SPACE + 1000
+	RCA_PREAMBLE_TOLERANCE = 200
+	RCA_BIT_TOLERANCE      = 120
 )
@@ -6,9 +6,11 @@ const (
 
 func NewIrpSamsung32(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencySamsung32,
-		decode:    DecodeSamsung32,
+		protocol:     protocol,
+		frequency:    FrequencySamsung32,
+		repeatPeriod: SAMSUNG_REPEAT_PERIOD,
+		minSplitTime: SAMSUNG_MIN_SPLIT_TIME,
+		decode:       DecodeSamsung32,
 	}
 }
 
@@ -59,6 +61,7 @@ const (
 	SAMSUNG_REPEAT_PAUSE1      = 46000
 	SAMSUNG_REPEAT_PAUSE2      = 97000
 	SAMSUNG_MIN_SPLIT_TIME     = 5000
+	SAMSUNG_REPEAT_PERIOD      = 108000
 	SAMSUNG_SILENCE            = 145000
 	SAMSUNG_REPEAT_PAUSE_MAX   = 140000
 	SAMSUNG_REPEAT_MARK        = 4500
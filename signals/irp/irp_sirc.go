@@ -8,25 +8,31 @@ const (
 
 func NewIrpSirc12(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencySirc12,
-		decode:    DecodeSirc12,
+		protocol:     protocol,
+		frequency:    FrequencySirc12,
+		repeatPeriod: SIRC_REPEAT_PERIOD,
+		minSplitTime: SIRC_MIN_SPLIT_TIME,
+		decode:       DecodeSirc12,
 	}
 }
 
 func NewIrpSirc15(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencySirc15,
-		decode:    DecodeSirc15,
+		protocol:     protocol,
+		frequency:    FrequencySirc15,
+		repeatPeriod: SIRC_REPEAT_PERIOD,
+		minSplitTime: SIRC_MIN_SPLIT_TIME,
+		decode:       DecodeSirc15,
 	}
 }
 
 func NewIrpSirc20(protocol string) Irp {
 	return &irpImpl{
-		protocol:  protocol,
-		frequency: FrequencySirc20,
-		decode:    DecodeSirc20,
+		protocol:     protocol,
+		frequency:    FrequencySirc20,
+		repeatPeriod: SIRC_REPEAT_PERIOD,
+		minSplitTime: SIRC_MIN_SPLIT_TIME,
+		decode:       DecodeSirc20,
 	}
 }
 
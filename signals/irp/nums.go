@@ -1,6 +1,7 @@
 package irp
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -90,6 +91,10 @@ func ParseHex32(str string) ([4]uint8, error) {
 	return result, nil
 }
 
+func FormatHex32(data [4]uint8) string {
+	return fmt.Sprintf("%02X %02X %02X %02X", data[0], data[1], data[2], data[3])
+}
+
 func ParseMicros(str string) (Micros, error) {
 	res, err := strconv.ParseUint(str, 10, 32)
 	if err != nil {
@@ -117,3 +122,14 @@ func SplitToMicrosArr(str string, separator string) ([]Micros, error) {
 	}
 	return data, nil
 }
+
+func JoinMicrosArr(data []Micros, separator string) string {
+	b := strings.Builder{}
+	for i, elem := range data {
+		if i != 0 {
+			b.WriteString(separator)
+		}
+		b.WriteString(strconv.FormatUint(uint64(elem), 10))
+	}
+	return b.String()
+}
@@ -80,3 +80,25 @@ func Test_Nums_Parse32(t *testing.T) {
 	testOk("0x aa bb cc", 0x00aabbcc)
 	testOk("0x aa bb cc dd", 0xaabbccdd)
 }
+
+func Test_Nums_FormatHex32(t *testing.T) {
+	assert.Equal(t, "00 00 00 00", FormatHex32(GetBytes32(0x00)))
+	assert.Equal(t, "01 23 45 67", FormatHex32(GetBytes32(0x01234567)))
+	assert.Equal(t, "AB CD EF 01", FormatHex32(GetBytes32(0xabcdef01)))
+
+	for _, str := range []string{"00 00 00 00", "04 00 00 00", "A0 B7 FF 01"} {
+		hex, err := ParseHex32(str)
+		assert.NoError(t, err, "str = '%v'", str)
+		assert.Equal(t, str, FormatHex32(hex))
+	}
+}
+
+func Test_Nums_JoinMicrosArr(t *testing.T) {
+	assert.Equal(t, "", JoinMicrosArr(nil, " "))
+	assert.Equal(t, "9000", JoinMicrosArr([]Micros{9000}, " "))
+	assert.Equal(t, "9000 4500 560", JoinMicrosArr([]Micros{9000, 4500, 560}, " "))
+
+	data, err := SplitToMicrosArr(JoinMicrosArr([]Micros{1, 2, 3}, ","), ",")
+	assert.NoError(t, err)
+	assert.Equal(t, []Micros{1, 2, 3}, data)
+}
@@ -0,0 +1,173 @@
+package irp
+
+type Recognition struct {
+	Protocol string     `json:"protocol"`
+	Code     SignalCode `json:"code"`
+	Length   int        `json:"length"`
+}
+
+// recognition is deliberately lenient, timings are checked against re-encoded data afterwards
+const recognizeToleranceScale = 2
+
+type recognizeFn func(data SignalData) (Recognition, bool)
+
+var supportedRecognizers = []recognizeFn{
+	recognizeNec,
+	recognizeSamsung32,
+	recognizeSirc,
+}
+
+func Recognize(data SignalData) (Recognition, bool) {
+	for _, recognize := range supportedRecognizers {
+		r, ok := recognize(data)
+		if ok {
+			return r, true
+		}
+	}
+	return Recognition{}, false
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func recognizeNec(data SignalData) (Recognition, bool) {
+	bytes, ok := recognizePulseDistance32(data, pulseDistanceTimings{
+		preambleMark:      NEC_PREAMBLE_MARK,
+		preambleSpace:     NEC_PREAMBLE_SPACE,
+		preambleTolerance: NEC_PREAMBLE_TOLERANCE,
+		bit1Space:         NEC_BIT1_SPACE,
+		bit0Space:         NEC_BIT0_SPACE,
+	})
+	if !ok {
+		return Recognition{}, false
+	}
+
+	r := Recognition{Length: 2 + 32*2 + 1}
+	if bytes[1] == ^bytes[0] && bytes[3] == ^bytes[2] {
+		r.Protocol = "NEC"
+		r.Code.Address[0] = bytes[0]
+		r.Code.Command[0] = bytes[2]
+	} else {
+		r.Protocol = "NECext"
+		r.Code.Address[0], r.Code.Address[1] = bytes[0], bytes[1]
+		r.Code.Command[0], r.Code.Command[1] = bytes[2], bytes[3]
+	}
+	return r, true
+}
+
+func recognizeSamsung32(data SignalData) (Recognition, bool) {
+	bytes, ok := recognizePulseDistance32(data, pulseDistanceTimings{
+		preambleMark:      SAMSUNG_PREAMBLE_MARK,
+		preambleSpace:     SAMSUNG_PREAMBLE_SPACE,
+		preambleTolerance: SAMSUNG_PREAMBLE_TOLERANCE,
+		bit1Space:         SAMSUNG_BIT1_SPACE,
+		bit0Space:         SAMSUNG_BIT0_SPACE,
+	})
+	if !ok || bytes[0] != bytes[1] || bytes[3] != ^bytes[2] {
+		return Recognition{}, false
+	}
+
+	r := Recognition{Protocol: "Samsung32", Length: 2 + 32*2 + 1}
+	r.Code.Address[0] = bytes[0]
+	r.Code.Command[0] = bytes[2]
+	return r, true
+}
+
+func recognizeSirc(data SignalData) (Recognition, bool) {
+	if len(data) < 2 ||
+		!isInTolerance(data[0], SIRC_PREAMBLE_MARK, SIRC_PREAMBLE_TOLERANCE*recognizeToleranceScale) ||
+		!isInTolerance(data[1], SIRC_PREAMBLE_SPACE, SIRC_PREAMBLE_TOLERANCE*recognizeToleranceScale) {
+		return Recognition{}, false
+	}
+
+	bits := make([]bool, 0, 20)
+	for i := 2; i < len(data) && len(bits) <= 20; i += 2 {
+		if data[i] > SIRC_BIT1_MARK*2 {
+			return Recognition{}, false
+		}
+		bits = append(bits, isCloserTo(data[i], SIRC_BIT1_MARK, SIRC_BIT0_MARK))
+
+		if i+1 >= len(data) || data[i+1] > SIRC_BIT0_SPACE*2 {
+			break
+		}
+	}
+
+	r := Recognition{Length: 2 + len(bits)*2 - 1}
+	r.Code.Command[0] = bitsToByte(bits, 0, 7)
+	switch len(bits) {
+	case 12:
+		r.Protocol = "SIRC"
+		r.Code.Address[0] = bitsToByte(bits, 7, 5)
+	case 15:
+		r.Protocol = "SIRC15"
+		r.Code.Address[0] = bitsToByte(bits, 7, 8)
+	case 20:
+		r.Protocol = "SIRC20"
+		r.Code.Address[0] = bitsToByte(bits, 7, 8)
+		r.Code.Address[1] = bitsToByte(bits, 15, 5)
+	default:
+		return Recognition{}, false
+	}
+
+	return r, true
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type pulseDistanceTimings struct {
+	preambleMark      Micros
+	preambleSpace     Micros
+	preambleTolerance Micros
+	bit1Space         Micros
+	bit0Space         Micros
+}
+
+func recognizePulseDistance32(data SignalData, t pulseDistanceTimings) ([4]uint8, bool) {
+	result := [4]uint8{}
+
+	const bitsCount = 32
+	if len(data) < 2+bitsCount*2+1 {
+		return result, false
+	}
+
+	if !isInTolerance(data[0], t.preambleMark, t.preambleTolerance*recognizeToleranceScale) ||
+		!isInTolerance(data[1], t.preambleSpace, t.preambleTolerance*recognizeToleranceScale) {
+		return result, false
+	}
+
+	bits := make([]bool, 0, bitsCount)
+	for i := 0; i < bitsCount; i++ {
+		mark, space := data[2+i*2], data[3+i*2]
+		if mark > t.bit1Space || space > t.bit1Space*2 {
+			return result, false
+		}
+		bits = append(bits, isCloserTo(space, t.bit1Space, t.bit0Space))
+	}
+
+	if data[2+bitsCount*2] > t.bit1Space {
+		return result, false
+	}
+
+	for i := range result {
+		result[i] = bitsToByte(bits, i*8, 8)
+	}
+
+	return result, true
+}
+
+func bitsToByte(bits []bool, offset int, count int) uint8 {
+	result := uint8(0)
+	for i := 0; i < count; i++ {
+		if bits[offset+i] {
+			result |= 1 << i
+		}
+	}
+	return result
+}
+
+func isCloserTo(actual, expected, other Micros) bool {
+	return absDiff(actual, expected) < absDiff(actual, other)
+}
+
+func isInTolerance(actual, expected, tolerance Micros) bool {
+	return absDiff(actual, expected) <= tolerance
+}
@@ -0,0 +1,39 @@
+package irp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Recognize_RoundTrip(t *testing.T) {
+	codes := []Recognition{
+		{Protocol: "NEC", Code: SignalCode{Address: [4]uint8{0x04}, Command: [4]uint8{0x08}}},
+		{Protocol: "NECext", Code: SignalCode{Address: [4]uint8{0x86, 0x6B}, Command: [4]uint8{0x12, 0x34}}},
+		{Protocol: "Samsung32", Code: SignalCode{Address: [4]uint8{0x07}, Command: [4]uint8{0x02}}},
+		{Protocol: "SIRC", Code: SignalCode{Address: [4]uint8{0x01}, Command: [4]uint8{0x15}}},
+		{Protocol: "SIRC15", Code: SignalCode{Address: [4]uint8{0x97}, Command: [4]uint8{0x3A}}},
+		{Protocol: "SIRC20", Code: SignalCode{Address: [4]uint8{0x5A, 0x11}, Command: [4]uint8{0x7F}}},
+	}
+
+	for _, expected := range codes {
+		p, err := GetIrp(strings.ToLower(expected.Protocol))
+		assert.NoError(t, err)
+		data, err := p.Decode(expected.Code)
+		assert.NoError(t, err)
+
+		actual, ok := Recognize(data)
+		assert.True(t, ok, expected.Protocol)
+		assert.Equal(t, expected.Protocol, actual.Protocol)
+		assert.Equal(t, expected.Code, actual.Code, expected.Protocol)
+
+		cmp := CompareSignalData(data, data, actual.Length, GetTolerances(actual.Protocol))
+		assert.Equal(t, 1.0, cmp.Confidence(), expected.Protocol)
+	}
+}
+
+func Test_Recognize_Unknown(t *testing.T) {
+	_, ok := Recognize(SignalData{300, 300, 900, 200})
+	assert.False(t, ok)
+}
@@ -60,6 +60,11 @@ func (this *SignalData) Duration() Micros {
 	return result
 }
 
+// pulses are marks, a trailing space does not add one
+func (this *SignalData) Pulses() int {
+	return (len(*this) + 1) / 2
+}
+
 func (this *SignalData) Clone() SignalData {
 	if this == nil {
 		return nil
@@ -1,5 +1,7 @@
 package irp
 
+import "irptools/utils/errs"
+
 /*
 
 /*
@@ -24,17 +26,14 @@ func ParseFrequency(str string) (Frequency, error) {
 }
 */
 
-/*
-
-func ValidateDurations(durations DurationsData) custom_error.CustomError {
-	return checks.Catch(func() {
-		checks.ThrowCheckPositiveInt("len(durations)", len(durations))
-		checks.ThrowCheckZeroInt("len(durations) % 2", len(durations)%2)
-		for i, d := range durations {
+func ValidateDurations(data SignalData) error {
+	return errs.Catch(func() {
+		errs.ThrowCheckPositive(len(data), "len(durations)")
+		errs.ThrowCheckZero(len(data)%2, "len(durations) % 2")
+		for i, d := range data {
 			if d == 0 {
-				checks.Throw(custom_error.MakeErrorf("durations[%v]==0", i))
+				errs.Throw(Errorf("durations[%v]==0", i))
 			}
 		}
 	})
 }
-*/
@@ -0,0 +1,200 @@
+package plan
+
+import (
+	"sort"
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+type Options struct {
+	Repeats    int        `json:"repeats" doc:"times every signal is sent"`
+	Gap        irp.Micros `json:"gap" doc:"pause between signals, us"`
+	RetuneTime irp.Micros `json:"retuneTime" doc:"time to retune the carrier frequency, us"`
+	Dedupe     bool       `json:"dedupe" doc:"send identical signals once"`
+}
+
+func (this Options) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckPositive(this.Repeats, "repeats")
+	})
+}
+
+func (this Options) Adjust() Options {
+	if this.Repeats == 0 {
+		this.Repeats = 1
+	}
+	return this
+}
+
+type Item struct {
+	Signal     signal.Signal
+	Popularity int
+	OnAirTime  irp.Micros
+}
+
+type Group struct {
+	Frequency  irp.Frequency `json:"frequency"`
+	Signals    int           `json:"signals"`
+	Popularity int           `json:"popularity"`
+	Time       irp.Micros    `json:"time"`
+}
+
+type Plan struct {
+	Items     []Item     `json:"-"`
+	Groups    []Group    `json:"groups"`
+	Signals   int        `json:"signals"`
+	Retunes   int        `json:"retunes"`
+	TotalTime irp.Micros `json:"totalTime"`
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func NewPlanner(options Options) *Planner {
+	return &Planner{
+		options: options,
+		brands:  map[string]map[string]struct{}{},
+		seen:    map[string]struct{}{},
+	}
+}
+
+type Planner struct {
+	options Options
+	items   []plannedItem
+	brands  map[string]map[string]struct{}
+	seen    map[string]struct{}
+}
+
+type plannedItem struct {
+	key   string
+	order int
+	Item
+}
+
+func (this *Planner) Consume(s signal.Signal) error {
+	key := signal.FingerprintOf(s)
+
+	brands, ok := this.brands[key]
+	if !ok {
+		brands = map[string]struct{}{}
+		this.brands[key] = brands
+	}
+	brands[strings.ToLower(s.Brand)] = struct{}{}
+
+	if this.options.Dedupe {
+		if _, ok := this.seen[key]; ok {
+			return nil
+		}
+		this.seen[key] = struct{}{}
+	}
+
+	this.items = append(this.items, plannedItem{
+		key:   key,
+		order: len(this.items),
+		Item: Item{
+			Signal:    s,
+			OnAirTime: OnAirTime(s),
+		},
+	})
+
+	return nil
+}
+
+func (this *Planner) Plan() Plan {
+	groups := map[irp.Frequency]*Group{}
+	for i := range this.items {
+		item := &this.items[i]
+		item.Popularity = len(this.brands[item.key])
+
+		group, ok := groups[item.Signal.Frequency]
+		if !ok {
+			group = &Group{Frequency: item.Signal.Frequency}
+			groups[item.Signal.Frequency] = group
+		}
+		group.Signals++
+		group.Popularity += item.Popularity
+		group.Time += this.slotTime(item.Item)
+	}
+
+	sortedGroups := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		sortedGroups = append(sortedGroups, *g)
+	}
+	sort.Slice(sortedGroups, func(i, j int) bool {
+		lhs, rhs := sortedGroups[i], sortedGroups[j]
+		if lhs.Popularity != rhs.Popularity {
+			return lhs.Popularity > rhs.Popularity
+		}
+		return lhs.Frequency < rhs.Frequency
+	})
+
+	groupRank := map[irp.Frequency]int{}
+	for i, g := range sortedGroups {
+		groupRank[g.Frequency] = i
+	}
+
+	items := make([]plannedItem, len(this.items))
+	copy(items, this.items)
+	sort.SliceStable(items, func(i, j int) bool {
+		lhs, rhs := items[i], items[j]
+		lhsRank, rhsRank := groupRank[lhs.Signal.Frequency], groupRank[rhs.Signal.Frequency]
+		if lhsRank != rhsRank {
+			return lhsRank < rhsRank
+		}
+		if lhs.Popularity != rhs.Popularity {
+			return lhs.Popularity > rhs.Popularity
+		}
+		if lhs.OnAirTime != rhs.OnAirTime {
+			return lhs.OnAirTime < rhs.OnAirTime
+		}
+		return lhs.order < rhs.order
+	})
+
+	plan := Plan{
+		Items:   make([]Item, 0, len(items)),
+		Groups:  sortedGroups,
+		Signals: len(items),
+	}
+
+	for _, item := range items {
+		plan.Items = append(plan.Items, item.Item)
+	}
+
+	for _, g := range sortedGroups {
+		plan.TotalTime += g.Time
+	}
+
+	if len(sortedGroups) > 1 {
+		plan.Retunes = len(sortedGroups) - 1
+		plan.TotalTime += irp.Micros(plan.Retunes) * this.options.RetuneTime
+	}
+
+	return plan
+}
+
+func (this *Planner) slotTime(item Item) irp.Micros {
+	return irp.Micros(this.options.Repeats)*item.OnAirTime + this.options.Gap
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func OnAirTime(s signal.Signal) irp.Micros {
+	duration := s.Data.Duration()
+	if s.Protocol == "" {
+		return duration
+	}
+
+	p, err := irp.GetIrp(strings.ToLower(s.Protocol))
+	if err != nil {
+		return duration
+	}
+
+	period := p.RepeatPeriod()
+	if duration < period {
+		return period
+	}
+
+	return duration
+}
@@ -0,0 +1,22 @@
+package signal
+
+// field names shared by csv import and export columns
+const (
+	FieldId          = "id"
+	FieldFingerprint = "fingerprint"
+	FieldSource      = "source"
+	FieldBrand       = "brand"
+	FieldDevice      = "device"
+	FieldModel       = "model"
+	FieldFunction    = "function"
+	FieldProtocol    = "protocol"
+	FieldAddress     = "address"
+	FieldCommand     = "command"
+	FieldFrequency   = "frequency"
+	FieldDuty        = "duty"
+	FieldDuration    = "duration"
+	FieldPulses      = "pulses"
+	FieldData        = "data"
+
+	FieldMetaPrefix = "meta."
+)
@@ -0,0 +1,48 @@
+package signal
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"irptools/signals/irp"
+)
+
+const (
+	FingerprintFrequencyQuantum irp.Frequency = 1000
+	FingerprintTimingQuantum    irp.Micros    = 50
+)
+
+func Fingerprint(s Signal) string {
+	b := strings.Builder{}
+	if s.Protocol != "" {
+		_, _ = fmt.Fprintf(&b, "parsed:%s:%s:%s",
+			strings.ToLower(s.Protocol), irp.FormatHex32(s.Code.Address), irp.FormatHex32(s.Code.Command))
+	} else {
+		_, _ = fmt.Fprintf(&b, "raw:%d:", quantize(s.Frequency, FingerprintFrequencyQuantum))
+		for i, d := range s.Data {
+			if i != 0 {
+				b.WriteByte(' ')
+			}
+			_, _ = fmt.Fprintf(&b, "%d", quantize(d, FingerprintTimingQuantum))
+		}
+	}
+	sum := sha1.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+func FingerprintOf(s Signal) string {
+	if s.Fingerprint != "" {
+		return s.Fingerprint
+	}
+	return Fingerprint(s)
+}
+
+func (this *Signal) UpdateFingerprint() {
+	this.Fingerprint = Fingerprint(*this)
+}
+
+func quantize[T ~uint](value T, quantum T) T {
+	return (value + quantum/2) / quantum * quantum
+}
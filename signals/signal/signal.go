@@ -8,15 +8,34 @@ import (
 )
 
 type Signal struct {
-	Id        string         `json:"id"`
-	Source    string         `json:"source"`
-	Brand     string         `json:"brand"`
-	Device    string         `json:"device"`
-	Function  string         `json:"function"`
-	Protocol  string         `json:"protocol"`
-	Frequency irp.Frequency  `json:"frequency"`
-	Data      irp.SignalData `json:"data"`
-	Code      irp.SignalCode `json:"code"`
+	Id          string         `json:"id"`
+	Fingerprint string         `json:"fingerprint,omitempty"`
+	Source      string         `json:"source"`
+	Brand       string         `json:"brand"`
+	Device      string         `json:"device"`
+	Model       string         `json:"model,omitempty"`
+	Function    string         `json:"function"`
+	Protocol    string         `json:"protocol"`
+	Frequency   irp.Frequency  `json:"frequency"`
+	Data        irp.SignalData `json:"data"`
+	Code        irp.SignalCode `json:"code"`
+	Meta        Meta           `json:"meta,omitempty"`
+}
+
+type Meta = map[string]string
+
+const (
+	MetaComment   = "comment"
+	MetaDutyCycle = "duty_cycle"
+
+	DefaultDutyCycle = "0.330000"
+)
+
+func (this *Signal) SetMeta(key string, value string) {
+	if this.Meta == nil {
+		this.Meta = Meta{}
+	}
+	this.Meta[key] = value
 }
 
 func (this *Signal) Format(s fmt.State, verb rune) {
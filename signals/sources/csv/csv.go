@@ -0,0 +1,187 @@
+package csv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+	jsonutils "irptools/utils/json"
+	"irptools/utils/logs"
+)
+
+type Options struct {
+	Ext                                string            `json:"ext"`
+	Delimiter                          string            `json:"delimiter"`
+	DataSeparator                      string            `json:"dataSeparator"`
+	Columns                            map[string]Header `json:"columns"`
+	IgnoreAllUnsupportedProtocolsError bool              `json:"ignoreAllUnsupportedProtocolError"`
+}
+
+func (this Options) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckNotZero(len(this.Columns), "len(columns)")
+		if utf8.RuneCountInString(this.Delimiter) != 1 {
+			errs.Throw(errs.Errorf("delimiter has to be a single character: '%s'", this.Delimiter))
+		}
+	})
+}
+
+// ext, delimiter and dataSeparator are optional, Adjust fills them before Validate
+func (this Options) Adjust() Options {
+	if this.Ext == "" {
+		this.Ext = ".csv"
+	}
+	if this.Delimiter == "" {
+		this.Delimiter = ","
+	}
+	if this.DataSeparator == "" {
+		this.DataSeparator = ","
+	}
+	this.Ext = strings.ToLower(this.Ext)
+	return this
+}
+
+type ClosableSignalConsumer interface {
+	Consume(signal signal.Signal) error
+	io.Closer
+}
+
+type SignalConsumerSource = func(filePath string) (ClosableSignalConsumer, error)
+
+func ParseCsvFiles(
+	ctx context.Context,
+	rootPath string,
+	opts interface{},
+	getConsumer SignalConsumerSource) (parsedSignalsCount int, err error) {
+
+	l := logs.L(ctx)
+
+	options := Options{}
+	err = jsonutils.CastStrict(opts, &options)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	options = options.Adjust()
+	err = errs.CheckValid(options, "options")
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	err = fs.EnumFilePathsWithExt(rootPath, options.Ext, func(filePath string) (res bool, err error) {
+		consumer, err := getConsumer(filePath)
+		if err != nil {
+			return false, errs.Wrap(err)
+		}
+
+		defer func() {
+			closeErr := consumer.Close()
+			if err == nil {
+				err = closeErr
+			}
+		}()
+
+		count, err := ParseCsvFile(filePath, options, consumer.Consume)
+
+		parsedSignalsCount += count
+		if err != nil {
+			l.I("ERR: %-4d: %s", count, filePath)
+		} else {
+			l.I(" OK: %-4d: %s", count, filePath)
+		}
+
+		if err != nil {
+			return false, errs.Errorf("failed to parse '%s': %w", filePath, err)
+		}
+
+		return true, errs.Wrap(err)
+	})
+
+	return parsedSignalsCount, errs.Wrap(err)
+}
+
+func ParseCsvFile(filePath string, options Options, consume SignalConsumer) (int, error) {
+	stream, err := fs.OpenReadOnlyFile(filePath)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	return parseCsvReader(filePath, stream, options, consume)
+}
+
+func ParseCsvReader(stream io.Reader, opts interface{}, consume SignalConsumer) (int, error) {
+	options := Options{}
+	err := jsonutils.CastStrict(opts, &options)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	options = options.Adjust()
+	err = errs.CheckValid(options, "options")
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	return parseCsvReader("", stream, options, consume)
+}
+
+func parseCsvReader(source string, stream io.Reader, options Options, consume SignalConsumer) (int, error) {
+	mapping, err := NewMapping(options.Columns, options.DataSeparator)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	count := 0
+	comma, _ := utf8.DecodeRuneInString(options.Delimiter)
+	err = ParseCsvStreamWithComma(stream, comma, mapping, func(s signal.Signal) error {
+		s.Source = source
+		s, err := completeSignal(s, options)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		s.UpdateFingerprint()
+		err = consume(s)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		count++
+		return nil
+	})
+
+	return count, errs.Wrap(err)
+}
+
+func completeSignal(s signal.Signal, options Options) (signal.Signal, error) {
+	if s.Protocol == "" || len(s.Data) != 0 {
+		return s, nil
+	}
+
+	p, err := irp.GetIrp(strings.ToLower(s.Protocol))
+	if err == nil {
+		s.Data, err = p.Decode(s.Code)
+	}
+
+	if err != nil {
+		upe := &irp.UnsupportedProtocolError{}
+		if options.IgnoreAllUnsupportedProtocolsError && errors.As(err, &upe) {
+			return s, nil
+		}
+		return s, errs.Errorf("failed to decode '%s' signal: %w", s.Protocol, err)
+	}
+
+	if s.Frequency == 0 {
+		s.Frequency = p.Frequency()
+	}
+
+	return s, nil
+}
@@ -0,0 +1,142 @@
+package csv
+
+import (
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+func NewMapping(columns map[string]Header, dataSeparator string) (Mapping, error) {
+	mapping := Mapping{}
+	for field, header := range columns {
+		set, err := newFieldSetter(field, dataSeparator)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		if _, ok := mapping[header]; ok {
+			return nil, errs.Errorf("column '%s' is mapped more than once", header)
+		}
+		mapping[header] = withFieldError(field, set)
+	}
+	return mapping, nil
+}
+
+func newFieldSetter(field string, dataSeparator string) (FieldSetter, error) {
+	if key, ok := strings.CutPrefix(field, signal.FieldMetaPrefix); ok {
+		if key == "" {
+			return nil, errs.Errorf("empty meta key: '%s'", field)
+		}
+		return func(s *signal.Signal, value string) error {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				s.SetMeta(key, value)
+			}
+			return nil
+		}, nil
+	}
+
+	switch field {
+	case signal.FieldId:
+		return stringSetter(func(s *signal.Signal) *string { return &s.Id }), nil
+	case signal.FieldBrand:
+		return stringSetter(func(s *signal.Signal) *string { return &s.Brand }), nil
+	case signal.FieldDevice:
+		return stringSetter(func(s *signal.Signal) *string { return &s.Device }), nil
+	case signal.FieldModel:
+		return stringSetter(func(s *signal.Signal) *string { return &s.Model }), nil
+	case signal.FieldFunction:
+		return stringSetter(func(s *signal.Signal) *string { return &s.Function }), nil
+	case signal.FieldProtocol:
+		return stringSetter(func(s *signal.Signal) *string { return &s.Protocol }), nil
+	case signal.FieldAddress:
+		return hex32Setter(func(s *signal.Signal) *[4]uint8 { return &s.Code.Address }), nil
+	case signal.FieldCommand:
+		return hex32Setter(func(s *signal.Signal) *[4]uint8 { return &s.Code.Command }), nil
+	case signal.FieldFrequency:
+		return setFrequency, nil
+	case signal.FieldDuty:
+		return setDutyCycle, nil
+	case signal.FieldData:
+		return func(s *signal.Signal, value string) error {
+			return setData(s, value, dataSeparator)
+		}, nil
+	case signal.FieldFingerprint, signal.FieldDuration, signal.FieldPulses:
+		return skipDerived, nil
+	}
+
+	return nil, errs.Errorf("unknown field: '%s'", field)
+}
+
+func withFieldError(field string, setter FieldSetter) FieldSetter {
+	return func(s *signal.Signal, value string) error {
+		err := setter(s, value)
+		if err != nil {
+			return errs.Errorf("field='%s', value='%s': %w", field, value, err)
+		}
+		return nil
+	}
+}
+
+func stringSetter(field func(s *signal.Signal) *string) FieldSetter {
+	return func(s *signal.Signal, value string) error {
+		*field(s) = strings.TrimSpace(value)
+		return nil
+	}
+}
+
+func hex32Setter(field func(s *signal.Signal) *[4]uint8) FieldSetter {
+	return func(s *signal.Signal, value string) error {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil
+		}
+		hex, err := irp.ParseHex32(value)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		*field(s) = hex
+		return nil
+	}
+}
+
+func setFrequency(s *signal.Signal, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	freq, err := irp.ParseFrequency(value)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	s.Frequency = freq
+	return nil
+}
+
+func setDutyCycle(s *signal.Signal, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" || value == signal.DefaultDutyCycle {
+		return nil
+	}
+	s.SetMeta(signal.MetaDutyCycle, value)
+	return nil
+}
+
+// derived columns written by export_csv are recomputed from the signal
+func skipDerived(s *signal.Signal, value string) error {
+	return nil
+}
+
+func setData(s *signal.Signal, value string, separator string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	data, err := irp.SplitToMicrosArr(value, separator)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	s.Data = data
+	return nil
+}
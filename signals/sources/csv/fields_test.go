@@ -0,0 +1,153 @@
+package csv
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/utils/misc"
+)
+
+func testCsvOptions() Options {
+	return Options{
+		Delimiter:     ";",
+		DataSeparator: " ",
+		Columns: map[string]Header{
+			signal.FieldBrand:                "Maker",
+			signal.FieldDevice:               "Kind",
+			signal.FieldFunction:             "Button",
+			signal.FieldProtocol:             "Proto",
+			signal.FieldAddress:              "Addr",
+			signal.FieldCommand:              "Cmd",
+			signal.FieldFrequency:            "Freq",
+			signal.FieldData:                 "Timings",
+			signal.FieldMetaPrefix + "room":  "Room",
+			signal.FieldMetaPrefix + "notes": "Notes",
+		},
+	}.Adjust()
+}
+
+func Test_Fields_ColumnMapping(t *testing.T) {
+	filePath, err := filepath.Abs(filepath.Join("testdata", "remotes.csv"))
+	assert.NoError(t, err)
+
+	signals := []signal.Signal{}
+	count, err := ParseCsvFile(filePath, testCsvOptions(), func(s signal.Signal) error {
+		signals = append(signals, s)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	tests := []struct {
+		brand     string
+		device    string
+		function  string
+		protocol  string
+		code      irp.SignalCode
+		frequency irp.Frequency
+		data      irp.SignalData
+		meta      signal.Meta
+	}{
+		{"LG", "TV", "Power", "NEC", irp.SignalCode{Address: [4]uint8{0x04}, Command: [4]uint8{0x08}}, 38000, nil, signal.Meta{"room": "living"}},
+		{"Sony", "TV", "Vol+", "SIRC", irp.SignalCode{Address: [4]uint8{0x01}, Command: [4]uint8{0x12}}, 40000, nil, signal.Meta{"room": "bedroom", "notes": "old remote"}},
+		{"Acme", "Fan", "Speed", "", irp.SignalCode{}, 36000, irp.SignalData{900, 450, 560, 40000}, nil},
+	}
+
+	for i, test := range tests {
+		s := signals[i]
+		assert.Equal(t, test.brand, s.Brand, test.function)
+		assert.Equal(t, test.device, s.Device, test.function)
+		assert.Equal(t, test.function, s.Function, test.function)
+		assert.Equal(t, test.protocol, s.Protocol, test.function)
+		assert.Equal(t, test.code, s.Code, test.function)
+		assert.Equal(t, test.frequency, s.Frequency, test.function)
+		assert.Equal(t, test.meta, s.Meta, test.function)
+		assert.Equal(t, filePath, s.Source, test.function)
+		if test.data != nil {
+			assert.Equal(t, test.data, s.Data, test.function)
+		} else {
+			assert.NotEmpty(t, s.Data, test.function)
+		}
+	}
+}
+
+func Test_Fields_MappingErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns map[string]Header
+	}{
+		{"unknown field", map[string]Header{"color": "Color"}},
+		{"empty meta key", map[string]Header{signal.FieldMetaPrefix: "Room"}},
+		{"header mapped twice", map[string]Header{signal.FieldBrand: "Name", signal.FieldFunction: "Name"}},
+	}
+
+	for _, test := range tests {
+		_, err := NewMapping(test.columns, ",")
+		assert.Error(t, err, test.name)
+	}
+}
+
+func Test_Fields_ParseErrors(t *testing.T) {
+	filePath := filepath.Join("testdata", "remotes.csv")
+	consume := func(s signal.Signal) error { return nil }
+
+	tests := []struct {
+		name    string
+		options func(o Options) Options
+	}{
+		{"missed header", func(o Options) Options { o.Columns[signal.FieldModel] = "Model"; return o }},
+		{"bad delimiter", func(o Options) Options { o.Delimiter = ","; return o }},
+		{"bad data separator", func(o Options) Options { o.DataSeparator = ","; return o }},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCsvFile(filePath, test.options(testCsvOptions()), consume)
+		assert.Error(t, err, test.name)
+	}
+}
+
+func Test_Fields_ExportImportRoundTrip(t *testing.T) {
+	parsed := signal.Signal{
+		Id: "lg-power", Brand: "LG", Device: "TV", Function: "Power", Protocol: "NEC", Frequency: 38000,
+		Code: irp.SignalCode{Address: [4]uint8{0x04}, Command: [4]uint8{0x08}},
+		Data: irp.SignalData{9000, 4500, 560, 40000},
+	}
+	raw := signal.Signal{
+		Id: "acme-speed", Brand: "Acme", Device: "Fan", Function: "Speed", Frequency: 36000,
+		Data: irp.SignalData{900, 450, 560, 40000},
+		Meta: signal.Meta{signal.MetaDutyCycle: "0.500000", "room": "hall"},
+	}
+
+	fields := append(append([]string{}, signalutils.DefaultSignalFields...), signal.FieldMetaPrefix+"room")
+	buf := &bytes.Buffer{}
+	writer, err := signalutils.NewCsvWriter(misc.NopWriteCloser(buf), signalutils.CsvFormat{Fields: fields, Delimiter: ';', DataSeparator: " "})
+	assert.NoError(t, err)
+	for _, s := range []signal.Signal{parsed, raw} {
+		assert.NoError(t, writer.Consume(s))
+	}
+	assert.NoError(t, writer.Close())
+
+	columns := map[string]Header{}
+	for _, field := range fields {
+		columns[field] = field
+	}
+
+	signals := []signal.Signal{}
+	count, err := ParseCsvReader(buf, Options{Delimiter: ";", DataSeparator: " ", Columns: columns}, func(s signal.Signal) error {
+		signals = append(signals, s)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	for i, expected := range []signal.Signal{parsed, raw} {
+		expected.UpdateFingerprint()
+		assert.Equal(t, expected, signals[i], expected.Function)
+	}
+}
@@ -28,7 +28,12 @@ type FastMapping = []struct {
 }
 
 func ParseCsvStream(stream io.Reader, mapping Mapping, consume SignalConsumer) error {
+	return ParseCsvStreamWithComma(stream, ',', mapping, consume)
+}
+
+func ParseCsvStreamWithComma(stream io.Reader, comma rune, mapping Mapping, consume SignalConsumer) error {
 	reader := csv.NewReader(stream)
+	reader.Comma = comma
 
 	headers, err := reader.Read()
 	if err != nil {
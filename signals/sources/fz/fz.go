@@ -3,8 +3,6 @@ package fz
 import (
 	"context"
 	"io"
-	"path/filepath"
-	"strings"
 
 	"irptools/signals/signal"
 	"irptools/utils/errs"
@@ -14,8 +12,9 @@ import (
 )
 
 type Options struct {
-	IgnoreAllUnsupportedProtocolsError      bool     `json:"ignoreAllUnsupportedProtocolError"`
-	IgnoreSpecificUnsupportedProtocolsError []string `json:"ignoreSpecificUnsupportedProtocolError"`
+	IgnoreAllUnsupportedProtocolsError      bool            `json:"ignoreAllUnsupportedProtocolError"`
+	IgnoreSpecificUnsupportedProtocolsError []string        `json:"ignoreSpecificUnsupportedProtocolError"`
+	PathInfo                                PathInfoOptions `json:"pathInfo"`
 }
 
 type SignalConsumer interface {
@@ -41,7 +40,12 @@ func ParseIrFiles(
 	l := logs.L(ctx)
 
 	options := Options{}
-	err := jsonutils.Cast(opts, &options)
+	err := jsonutils.CastStrict(opts, &options)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	pathInfo, err := NewPathInfoExtractor(rootPath, options.PathInfo)
 	if err != nil {
 		return 0, errs.Wrap(err)
 	}
@@ -61,7 +65,7 @@ func ParseIrFiles(
 			}
 		}()
 
-		count, err := ParseIrFile(filePath, options, consumer)
+		count, err := parseIrFile(filePath, pathInfo, options, consumer)
 		parsedSignalsCount += count
 		if err != nil {
 			l.I("ERR: %-4d: %s", count, filePath)
@@ -79,7 +83,36 @@ func ParseIrFiles(
 	return parsedSignalsCount, errs.Wrap(err)
 }
 
-func ParseIrFile(filePath string, options Options, consumer SignalConsumer) (int, error) {
+func ParseIrFile(rootPath string, filePath string, options Options, consumer SignalConsumer) (int, error) {
+	pathInfo, err := NewPathInfoExtractor(rootPath, options.PathInfo)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+	return parseIrFile(filePath, pathInfo, options, consumer)
+}
+
+func ParseIrReader(stream io.Reader, opts interface{}, consumer SignalConsumer) (int, error) {
+	options := Options{}
+	err := jsonutils.CastStrict(opts, &options)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	cfg := parseCfg{
+		ignoreAllUnsupportedProtocols:      options.IgnoreAllUnsupportedProtocolsError,
+		ignoreSpecificUnsupportedProtocols: options.IgnoreSpecificUnsupportedProtocolsError,
+	}
+
+	c, err := ParseIrStream(cfg, stream, consumer)
+	return c, errs.Wrap(err)
+}
+
+func parseIrFile(filePath string, pathInfo *PathInfoExtractor, options Options, consumer SignalConsumer) (int, error) {
+	info, err := pathInfo.Extract(filePath)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
 	stream, err := fs.OpenReadOnlyFile(filePath)
 	if err != nil {
 		return 0, errs.Wrap(err)
@@ -91,7 +124,9 @@ func ParseIrFile(filePath string, options Options, consumer SignalConsumer) (int
 
 	cfg := parseCfg{
 		source:                             filePath,
-		brand:                              brandFromFilePath(filePath),
+		brand:                              info.Brand,
+		device:                             info.Device,
+		model:                              info.Model,
 		ignoreAllUnsupportedProtocols:      options.IgnoreAllUnsupportedProtocolsError,
 		ignoreSpecificUnsupportedProtocols: options.IgnoreSpecificUnsupportedProtocolsError,
 	}
@@ -99,12 +134,3 @@ func ParseIrFile(filePath string, options Options, consumer SignalConsumer) (int
 	c, err := ParseIrStream(cfg, stream, consumer)
 	return c, errs.Wrap(err)
 }
-
-func brandFromFilePath(filePath string) string {
-	fileName := filepath.Base(filePath)
-	separatorPos := strings.IndexAny(fileName, "_-.")
-	if separatorPos > 0 {
-		return fileName[0:separatorPos]
-	}
-	return fileName
-}
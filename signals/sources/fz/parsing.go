@@ -14,6 +14,8 @@ import (
 
 type parseCfg struct {
 	brand                              string
+	device                             string
+	model                              string
 	source                             string
 	ignoreAllUnsupportedProtocols      bool
 	ignoreSpecificUnsupportedProtocols []string
@@ -40,6 +42,9 @@ func ParseIrStream(
 		s.Id = nextId()
 		s.Source = cfg.source
 		s.Brand = cfg.brand
+		s.Device = cfg.device
+		s.Model = cfg.model
+		s.UpdateFingerprint()
 		return s
 	}
 
@@ -68,7 +73,7 @@ func ParseIrStream(
 
 	lines := fp.Filter(isStreamLineValid,
 		fp.Flow(fp.FnR2RE(strings.TrimSpace),
-			linesToBatch))
+			withCommentsMovedToNextBlock(linesToBatch)))
 
 	stream = io.MultiReader(stream, strings.NewReader("\n"+signalsEndOfLines))
 	err := misc.EnumStreamLines(io.NopCloser(stream), func(line string) (bool, error) {
@@ -78,6 +83,42 @@ func ParseIrStream(
 	return signalsCount, errs.Wrap(err)
 }
 
+func withCommentsMovedToNextBlock(next fp.FnE[string]) fp.FnE[string] {
+	pendingComments := LinesBatch{}
+
+	flush := func() error {
+		for _, comment := range pendingComments {
+			err := next(comment)
+			if err != nil {
+				return err
+			}
+		}
+		pendingComments = LinesBatch{}
+		return nil
+	}
+
+	return func(line string) error {
+		if isCommentLine(line) {
+			pendingComments = append(pendingComments, line)
+			return nil
+		}
+
+		if isLinesBatchSplitter(line) {
+			err := next(line)
+			if err != nil {
+				return err
+			}
+			return flush()
+		}
+
+		err := flush()
+		if err != nil {
+			return err
+		}
+		return next(line)
+	}
+}
+
 func isLinesBatchEmpty(batch LinesBatch) bool {
 	return len(batch) == 0
 }
@@ -97,6 +138,18 @@ func fieldsFromLinesBatch(lines LinesBatch) (FieldsMap, error) {
 	fields := FieldsMap{}
 
 	processLine := func(line string) error {
+		if isCommentLine(line) {
+			comment := strings.TrimSpace(line[1:])
+			if comment == "" {
+				return nil
+			}
+			if prev, ok := fields[signalFieldComment]; ok {
+				comment = prev + "\n" + comment
+			}
+			fields[signalFieldComment] = comment
+			return nil
+		}
+
 		key, value, err := parseLine(line)
 		if err != nil {
 			return errs.Wrap(err)
@@ -131,14 +184,11 @@ func fieldsFromLinesBatch(lines LinesBatch) (FieldsMap, error) {
 }
 
 func isStreamLineValid(line string) bool {
-	line = strings.TrimSpace(line)
-	if line == "" {
-		return false
-	}
-	if line[0] == '#' {
-		return false
-	}
-	return true
+	return strings.TrimSpace(line) != ""
+}
+
+func isCommentLine(line string) bool {
+	return len(line) > 0 && line[0] == '#'
 }
 
 func isLinesBatchSplitter(line string) bool {
@@ -0,0 +1,113 @@
+package fz
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+)
+
+func Test_Parsing_CommentsMovedToNextBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected []string
+	}{
+		{
+			"comment before block",
+			[]string{"# power", "name: Power", "type: parsed"},
+			[]string{"name: Power", "# power", "type: parsed"},
+		},
+		{
+			"comments between blocks",
+			[]string{"name: Power", "type: parsed", "# mute", "# sound", "name: Mute"},
+			[]string{"name: Power", "type: parsed", "name: Mute", "# mute", "# sound"},
+		},
+		{
+			"comment inside block",
+			[]string{"name: Power", "# inline", "type: parsed"},
+			[]string{"name: Power", "# inline", "type: parsed"},
+		},
+		{
+			"trailing comment",
+			[]string{"name: Power", "# trailing", signalsEndOfLines},
+			[]string{"name: Power", signalsEndOfLines, "# trailing"},
+		},
+	}
+
+	for _, test := range tests {
+		actual := []string{}
+		next := withCommentsMovedToNextBlock(func(line string) error {
+			actual = append(actual, line)
+			return nil
+		})
+		for _, line := range test.lines {
+			assert.NoError(t, next(line), test.name)
+		}
+		assert.Equal(t, test.expected, actual, test.name)
+	}
+}
+
+func Test_Parsing_MetaFromFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		fields   FieldsMap
+		expected signal.Meta
+	}{
+		{"known only", "parsed", FieldsMap{"name": "Power", "type": "parsed", "protocol": "NEC"}, nil},
+		{"comment", "parsed", FieldsMap{"name": "Power", "#": "power\nbutton"}, signal.Meta{signal.MetaComment: "power\nbutton"}},
+		{"unknown", "parsed", FieldsMap{"name": "Power", "button": "red"}, signal.Meta{"button": "red"}},
+		{"both", "raw", FieldsMap{"#": "c", "duty_cycle": "0.33"}, signal.Meta{signal.MetaComment: "c", "duty_cycle": "0.33"}},
+		{"raw fields of parsed", "parsed", FieldsMap{"name": "Power", "frequency": "38000"}, signal.Meta{"frequency": "38000"}},
+		{"parsed fields of raw", "raw", FieldsMap{"name": "Power", "protocol": "NEC", "command": "08 00 00 00"}, signal.Meta{"protocol": "NEC", "command": "08 00 00 00"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, metaFromFields(test.fields, knownSignalFields[test.typ]), test.name)
+	}
+}
+
+func Test_Parsing_CommentsFixture(t *testing.T) {
+	root, err := filepath.Abs("testdata")
+	assert.NoError(t, err)
+
+	metas := map[string]signal.Meta{}
+	consumer := signalutils.SignalConsumerFn(func(s signal.Signal) error {
+		metas[s.Function] = s.Meta
+		return nil
+	})
+
+	count, err := ParseIrFile(root, filepath.Join(root, "TVs", "LG", "LG_OLED55.ir"), Options{}, consumer)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, map[string]signal.Meta{
+		"Power":  {signal.MetaComment: "LG OLED remote"},
+		"Mute":   {signal.MetaComment: "Mutes the sound\nand unmutes it", "button": "red"},
+		"Vol_up": {signal.MetaDutyCycle: "0.330000"},
+	}, metas)
+
+	count, err = ParseIrStream(parseCfg{}, strings.NewReader(`name: Power
+type: parsed
+protocol: NEC
+address: 04 00 00 00
+command: 08 00 00 00
+frequency: 38000
+# dangling
+name: Vol_up
+type: raw
+frequency: 38000
+data: 9000 4500 560 40000
+protocol: NEC
+address: 04 00 00 00
+command: 02 00 00 00
+`), consumer)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, signal.Meta{"frequency": "38000"}, metas["Power"])
+	assert.Equal(t, signal.Meta{signal.MetaComment: "dangling", "protocol": "NEC", "address": "04 00 00 00", "command": "02 00 00 00"}, metas["Vol_up"])
+}
@@ -0,0 +1,180 @@
+package fz
+
+import (
+	"encoding/json"
+	"errors"
+	iofs "io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+)
+
+type PathInfoOptions struct {
+	Segments   PathSegmentsOptions `json:"segments"`
+	Regex      string              `json:"regex"`
+	SidecarExt string              `json:"sidecarExt"`
+}
+
+type PathSegmentsOptions struct {
+	Brand  *int `json:"brand"`
+	Device *int `json:"device"`
+	Model  *int `json:"model"`
+}
+
+type PathInfo struct {
+	Brand  string `json:"brand"`
+	Device string `json:"device"`
+	Model  string `json:"model"`
+}
+
+func (this PathInfo) Merge(other PathInfo) PathInfo {
+	if other.Brand != "" {
+		this.Brand = other.Brand
+	}
+	if other.Device != "" {
+		this.Device = other.Device
+	}
+	if other.Model != "" {
+		this.Model = other.Model
+	}
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func NewPathInfoExtractor(rootPath string, options PathInfoOptions) (*PathInfoExtractor, error) {
+	extractor := &PathInfoExtractor{
+		rootPath: rootPath,
+		options:  options,
+	}
+
+	if options.Regex != "" {
+		re, err := regexp.Compile(options.Regex)
+		if err != nil {
+			return nil, errs.Errorf("bad path info regex: %w", err)
+		}
+		extractor.re = re
+	}
+
+	return extractor, nil
+}
+
+type PathInfoExtractor struct {
+	rootPath string
+	options  PathInfoOptions
+	re       *regexp.Regexp
+}
+
+func (this *PathInfoExtractor) Extract(filePath string) (PathInfo, error) {
+	relPath := this.relativePath(filePath)
+
+	info := PathInfo{}
+	info = info.Merge(this.fromSegments(relPath))
+	info = info.Merge(this.fromRegex(relPath))
+
+	sidecar, err := this.fromSidecar(filePath)
+	if err != nil {
+		return info, errs.Wrap(err)
+	}
+	info = info.Merge(sidecar)
+
+	if info.Brand == "" {
+		info.Brand = brandFromFilePath(filePath)
+	}
+
+	return info, nil
+}
+
+func (this *PathInfoExtractor) relativePath(filePath string) string {
+	relPath, err := filepath.Rel(this.rootPath, filePath)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		relPath = filepath.Base(filePath)
+	}
+	return fs.AdjustPathSlash(relPath)
+}
+
+func (this *PathInfoExtractor) fromSegments(relPath string) PathInfo {
+	segments := strings.Split(strings.TrimSuffix(relPath, filepath.Ext(relPath)), "/")
+
+	segment := func(idx *int) string {
+		if idx == nil {
+			return ""
+		}
+		i := *idx
+		if i < 0 {
+			i += len(segments)
+		}
+		if i < 0 || i >= len(segments) {
+			return ""
+		}
+		return segments[i]
+	}
+
+	return PathInfo{
+		Brand:  segment(this.options.Segments.Brand),
+		Device: segment(this.options.Segments.Device),
+		Model:  segment(this.options.Segments.Model),
+	}
+}
+
+func (this *PathInfoExtractor) fromRegex(relPath string) PathInfo {
+	info := PathInfo{}
+	if this.re == nil {
+		return info
+	}
+
+	match := this.re.FindStringSubmatch(relPath)
+	if match == nil {
+		return info
+	}
+
+	for i, name := range this.re.SubexpNames() {
+		switch name {
+		case "brand":
+			info.Brand = match[i]
+		case "device":
+			info.Device = match[i]
+		case "model":
+			info.Model = match[i]
+		}
+	}
+
+	return info
+}
+
+func (this *PathInfoExtractor) fromSidecar(filePath string) (PathInfo, error) {
+	info := PathInfo{}
+	if this.options.SidecarExt == "" {
+		return info, nil
+	}
+
+	sidecarPath := strings.TrimSuffix(filePath, filepath.Ext(filePath)) + this.options.SidecarExt
+	data, err := fs.ReadFile(sidecarPath)
+	if err != nil {
+		if errors.Is(err, iofs.ErrNotExist) {
+			return info, nil
+		}
+		return info, errs.Wrap(err)
+	}
+
+	err = json.Unmarshal(data, &info)
+	if err != nil {
+		return info, errs.Errorf("bad sidecar '%s': %w", sidecarPath, err)
+	}
+
+	return info, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func brandFromFilePath(filePath string) string {
+	fileName := filepath.Base(filePath)
+	separatorPos := strings.IndexAny(fileName, "_-.")
+	if separatorPos > 0 {
+		return fileName[0:separatorPos]
+	}
+	return fileName
+}
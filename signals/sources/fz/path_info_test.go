@@ -0,0 +1,89 @@
+package fz
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PathInfo_Extract(t *testing.T) {
+	first, second, last, outOfRange := 0, 1, -1, 5
+
+	tests := []struct {
+		name     string
+		options  PathInfoOptions
+		filePath string
+		expected PathInfo
+	}{
+		{"file name", PathInfoOptions{}, "TVs/LG/LG_OLED55.ir", PathInfo{Brand: "LG"}},
+		{"no separator", PathInfoOptions{}, "TVs/Sony/Bravia.ir", PathInfo{Brand: "Bravia"}},
+		{
+			"segments",
+			PathInfoOptions{Segments: PathSegmentsOptions{Device: &first, Brand: &second, Model: &last}},
+			"TVs/LG/LG_OLED55.ir",
+			PathInfo{Brand: "LG", Device: "TVs", Model: "LG_OLED55"},
+		},
+		{
+			"segment out of range",
+			PathInfoOptions{Segments: PathSegmentsOptions{Brand: &second, Device: &outOfRange}},
+			"TVs/Sony/Bravia.ir",
+			PathInfo{Brand: "Sony"},
+		},
+		{
+			"regex",
+			PathInfoOptions{Regex: `^(?P<device>[^/]+)/(?P<brand>[^/]+)/[^_]+_(?P<model>[^.]+)\.ir$`},
+			"TVs/LG/LG_OLED55.ir",
+			PathInfo{Brand: "LG", Device: "TVs", Model: "OLED55"},
+		},
+		{
+			"regex overrides segments",
+			PathInfoOptions{Segments: PathSegmentsOptions{Brand: &first}, Regex: `/(?P<brand>[^/]+)/`},
+			"TVs/Sony/Bravia.ir",
+			PathInfo{Brand: "Sony"},
+		},
+		{
+			"sidecar",
+			PathInfoOptions{SidecarExt: ".json"},
+			"TVs/LG/LG_OLED55.ir",
+			PathInfo{Brand: "LG", Model: "OLED55C1"},
+		},
+		{
+			"sidecar overrides regex",
+			PathInfoOptions{Regex: `_(?P<model>[^.]+)\.ir$`, SidecarExt: ".json"},
+			"TVs/LG/LG_OLED55.ir",
+			PathInfo{Brand: "LG", Model: "OLED55C1"},
+		},
+		{
+			"missing sidecar",
+			PathInfoOptions{Segments: PathSegmentsOptions{Brand: &second}, SidecarExt: ".json"},
+			"TVs/Sony/Bravia.ir",
+			PathInfo{Brand: "Sony"},
+		},
+	}
+
+	root, err := filepath.Abs("testdata")
+	assert.NoError(t, err)
+
+	for _, test := range tests {
+		extractor, err := NewPathInfoExtractor(root, test.options)
+		assert.NoError(t, err, test.name)
+
+		info, err := extractor.Extract(filepath.Join(root, filepath.FromSlash(test.filePath)))
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, info, test.name)
+	}
+}
+
+func Test_PathInfo_Errors(t *testing.T) {
+	root, err := filepath.Abs("testdata")
+	assert.NoError(t, err)
+
+	_, err = NewPathInfoExtractor(root, PathInfoOptions{Regex: `(?P<brand>`})
+	assert.Error(t, err)
+
+	extractor, err := NewPathInfoExtractor(root, PathInfoOptions{SidecarExt: ".json"})
+	assert.NoError(t, err)
+	_, err = extractor.Extract(filepath.Join(root, "bad", "Bad.ir"))
+	assert.Error(t, err)
+}
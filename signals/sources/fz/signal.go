@@ -21,6 +21,9 @@ func signalFromFields(fields FieldsMap, checker *ErrorChecker) (signal.Signal, e
 		}
 		var err error
 		s, err = decodeSignal(&d, fields)
+		if err == nil {
+			s.Meta = metaFromFields(fields, knownSignalFields[value])
+		}
 		return errs.Wrap(err)
 	})
 
@@ -38,6 +41,23 @@ func signalFromFields(fields FieldsMap, checker *ErrorChecker) (signal.Signal, e
 	return s, errs.Wrap(err)
 }
 
+func metaFromFields(fields FieldsMap, known map[string]struct{}) signal.Meta {
+	var meta signal.Meta
+	for k, v := range fields {
+		if _, ok := known[k]; ok {
+			continue
+		}
+		if meta == nil {
+			meta = signal.Meta{}
+		}
+		if k == signalFieldComment {
+			k = signal.MetaComment
+		}
+		meta[k] = v
+	}
+	return meta
+}
+
 type decodeSignalFn func(d *decoder, fields FieldsMap) (signal.Signal, error)
 
 var signalDecoders = map[string]decodeSignalFn{
@@ -65,6 +85,14 @@ func (this *checkingIrp) Frequency() irp.Frequency {
 	return this.origin.Frequency()
 }
 
+func (this *checkingIrp) RepeatPeriod() irp.Micros {
+	return this.origin.RepeatPeriod()
+}
+
+func (this *checkingIrp) MinSplitTime() irp.Micros {
+	return this.origin.MinSplitTime()
+}
+
 func (this *checkingIrp) Decode(code irp.SignalCode) (irp.SignalData, error) {
 	data, err := this.origin.Decode(code)
 	if err == nil {
@@ -185,4 +213,21 @@ const (
 	signalFieldCommand   = "command"
 	signalFieldAddress   = "address"
 	signalFieldData      = "data"
+	signalFieldComment   = "#"
 )
+
+var knownSignalFields = map[string]map[string]struct{}{
+	"parsed": {
+		signalFieldName:     {},
+		signalFieldType:     {},
+		signalFieldProtocol: {},
+		signalFieldAddress:  {},
+		signalFieldCommand:  {},
+	},
+	"raw": {
+		signalFieldName:      {},
+		signalFieldType:      {},
+		signalFieldFrequency: {},
+		signalFieldData:      {},
+	},
+}
@@ -24,6 +24,7 @@ func ParseCsvStream(
 	count := 0
 	err := csv.ParseCsvStream(stream, csvMapping, func(signal signal.Signal) error {
 		signal.Source = cfg.source
+		signal.UpdateFingerprint()
 		err := consumer.Consume(signal)
 		if err != nil {
 			return errs.Wrap(err)
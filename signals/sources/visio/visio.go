@@ -36,7 +36,7 @@ func ParseCsvFiles(
 	l := logs.L(ctx)
 
 	options := Options{}
-	err = jsonutils.Cast(opts, &options)
+	err = jsonutils.CastStrict(opts, &options)
 	if err != nil {
 		return 0, errs.Wrap(err)
 	}
@@ -132,6 +132,11 @@ func (this *splittingConsumer) Close() error {
 	return nil
 }
 
+func ParseCsvReader(stream io.Reader, consumer SignalConsumer) (int, error) {
+	c, err := ParseCsvStream(parseCfg{}, stream, consumer)
+	return c, errs.Wrap(err)
+}
+
 func ParseCsvFile(filePath string, options Options, consumer SignalConsumer) (int, error) {
 	stream, err := fs.OpenReadOnlyFile(filePath)
 	if err != nil {
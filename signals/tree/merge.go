@@ -0,0 +1,111 @@
+package tree
+
+import (
+	"sort"
+	"strings"
+
+	"irptools/utils/errs"
+)
+
+const (
+	SideLeft  = "left"
+	SideRight = "right"
+)
+
+type Precedence struct {
+	Default string            `json:"default" doc:"side that wins by default: left or right"`
+	Fields  map[string]string `json:"fields" doc:"side that wins per field"`
+}
+
+func (this Precedence) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowIf(validateSide(this.Default, "default"))
+		for field, side := range this.Fields {
+			errs.ThrowIf(validateSide(side, field))
+			found := false
+			for _, f := range metadataFields {
+				found = found || f == field
+			}
+			if !found {
+				errs.Throw(errs.Errorf("unsupported precedence field: '%s'", field))
+			}
+		}
+	})
+}
+
+func (this Precedence) Adjust() Precedence {
+	if this.Default == "" {
+		this.Default = SideLeft
+	}
+	return this
+}
+
+func validateSide(side string, name string) error {
+	if side != SideLeft && side != SideRight {
+		return errs.Errorf("%s: unexpected side '%s', expected '%s' or '%s'", name, side, SideLeft, SideRight)
+	}
+	return nil
+}
+
+func (this Precedence) side(field string) string {
+	if side, ok := this.Fields[field]; ok {
+		return side
+	}
+	return this.Default
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Merged struct {
+	Entry
+	Root string
+	Side string
+}
+
+func Merge(left, right Tree, c Comparison, precedence Precedence) []Merged {
+	result := make([]Merged, 0, len(c.Pairs)+len(c.OnlyLeft)+len(c.OnlyRight))
+
+	for _, p := range c.Pairs {
+		winner, winnerSide, other := p.Left, SideLeft, p.Right
+		if precedence.Default == SideRight {
+			winner, winnerSide, other = p.Right, SideRight, p.Left
+		}
+
+		merged := winner
+		for _, field := range metadataFields {
+			if precedence.side(field) != precedence.Default {
+				setFieldValue(&merged.Signal, field, other.Signal)
+			}
+		}
+		result = append(result, newMerged(merged, winnerSide, left, right))
+	}
+
+	for _, e := range c.OnlyLeft {
+		result = append(result, newMerged(e, SideLeft, left, right))
+	}
+	for _, e := range c.OnlyRight {
+		result = append(result, newMerged(e, SideRight, left, right))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Side != result[j].Side {
+			return result[i].Side == SideLeft
+		}
+		return result[i].Index < result[j].Index
+	})
+
+	return result
+}
+
+func newMerged(e Entry, side string, left, right Tree) Merged {
+	if side == SideLeft {
+		return Merged{Entry: e, Root: left.Root, Side: side}
+	}
+	return Merged{Entry: e, Root: right.Root, Side: side}
+}
+
+func sortConflicts(conflicts []Conflict) {
+	sort.Slice(conflicts, func(i, j int) bool {
+		return strings.Compare(conflicts[i].Field, conflicts[j].Field) < 0
+	})
+}
@@ -0,0 +1,68 @@
+package tree
+
+type ReportEntry struct {
+	Left      string     `json:"left,omitempty"`
+	Right     string     `json:"right,omitempty"`
+	Brand     string     `json:"brand"`
+	Function  string     `json:"function"`
+	Protocol  string     `json:"protocol"`
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+}
+
+type Report struct {
+	Left        string        `json:"left"`
+	Right       string        `json:"right"`
+	Matched     int           `json:"matched"`
+	Conflicting int           `json:"conflicting"`
+	OnlyLeft    int           `json:"onlyLeft"`
+	OnlyRight   int           `json:"onlyRight"`
+	Conflicts   []ReportEntry `json:"conflicts"`
+	Added       []ReportEntry `json:"added,omitempty"`
+	Removed     []ReportEntry `json:"removed,omitempty"`
+}
+
+func NewReport(left, right Tree, c Comparison, withOnly bool) Report {
+	r := Report{
+		Left:      left.Root,
+		Right:     right.Root,
+		Matched:   len(c.Pairs),
+		OnlyLeft:  len(c.OnlyLeft),
+		OnlyRight: len(c.OnlyRight),
+		Conflicts: []ReportEntry{},
+	}
+
+	for _, p := range c.Pairs {
+		if len(p.Conflicts) == 0 {
+			continue
+		}
+		r.Conflicting++
+		entry := newReportEntry(p.Left)
+		entry.Left = left.Ref(p.Left)
+		entry.Right = right.Ref(p.Right)
+		entry.Conflicts = p.Conflicts
+		r.Conflicts = append(r.Conflicts, entry)
+	}
+
+	if withOnly {
+		for _, e := range c.OnlyLeft {
+			entry := newReportEntry(e)
+			entry.Left = left.Ref(e)
+			r.Removed = append(r.Removed, entry)
+		}
+		for _, e := range c.OnlyRight {
+			entry := newReportEntry(e)
+			entry.Right = right.Ref(e)
+			r.Added = append(r.Added, entry)
+		}
+	}
+
+	return r
+}
+
+func newReportEntry(e Entry) ReportEntry {
+	return ReportEntry{
+		Brand:    e.Signal.Brand,
+		Function: e.Signal.Function,
+		Protocol: e.Signal.Protocol,
+	}
+}
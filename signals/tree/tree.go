@@ -0,0 +1,222 @@
+package tree
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/utils/errs"
+)
+
+type Entry struct {
+	Signal   signal.Signal
+	FilePath string
+	Index    int
+}
+
+type Tree struct {
+	Root    string
+	Entries []Entry
+}
+
+func (this Tree) Ref(e Entry) string {
+	relPath, err := filepath.Rel(this.Root, e.FilePath)
+	if err != nil {
+		relPath = e.FilePath
+	}
+	return filepath.ToSlash(relPath) + "#" + e.Signal.Id
+}
+
+func Load(ctx context.Context, root string) (Tree, error) {
+	t := Tree{Root: root}
+	err := signalutils.EnumSignals(ctx, root, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(signalutils.SignalConsumerFn(func(s signal.Signal) error {
+			t.Entries = append(t.Entries, Entry{Signal: s, FilePath: filePath, Index: len(t.Entries)})
+			return nil
+		})), nil
+	})
+	return t, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	FieldBrand    = "brand"
+	FieldDevice   = "device"
+	FieldModel    = "model"
+	FieldFunction = "function"
+	FieldCode     = "code"
+	FieldMeta     = "meta"
+)
+
+var metadataFields = []string{FieldBrand, FieldDevice, FieldModel, FieldFunction, FieldMeta}
+
+var defaultMatchBy = []string{FieldBrand, FieldCode}
+
+type MatchOptions struct {
+	By []string `json:"by" doc:"fields signals are matched by"`
+}
+
+func (this MatchOptions) Validate() error {
+	return errs.Catch(func() {
+		for _, field := range this.By {
+			switch field {
+			case FieldBrand, FieldDevice, FieldModel, FieldFunction, FieldCode:
+			default:
+				errs.Throw(errs.Errorf("unsupported match field: '%s'", field))
+			}
+		}
+	})
+}
+
+func (this MatchOptions) Adjust() MatchOptions {
+	if len(this.By) == 0 {
+		this.By = defaultMatchBy
+	}
+	return this
+}
+
+func (this MatchOptions) Identity(s signal.Signal) string {
+	parts := make([]string, 0, len(this.By))
+	for _, field := range this.By {
+		if field == FieldCode {
+			parts = append(parts, signal.FingerprintOf(s))
+		} else {
+			parts = append(parts, strings.ToLower(fieldValue(s, field)))
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func (this MatchOptions) comparedFields() []string {
+	fields := make([]string, 0, len(metadataFields))
+	for _, field := range metadataFields {
+		matched := false
+		for _, by := range this.By {
+			matched = matched || by == field
+		}
+		if !matched {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func fieldValue(s signal.Signal, field string) string {
+	switch field {
+	case FieldBrand:
+		return s.Brand
+	case FieldDevice:
+		return s.Device
+	case FieldModel:
+		return s.Model
+	case FieldFunction:
+		return s.Function
+	}
+	return ""
+}
+
+func setFieldValue(s *signal.Signal, field string, from signal.Signal) {
+	switch field {
+	case FieldBrand:
+		s.Brand = from.Brand
+	case FieldDevice:
+		s.Device = from.Device
+	case FieldModel:
+		s.Model = from.Model
+	case FieldFunction:
+		s.Function = from.Function
+	case FieldMeta:
+		s.Meta = from.Meta
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Conflict struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+type Pair struct {
+	Left      Entry
+	Right     Entry
+	Conflicts []Conflict
+}
+
+type Comparison struct {
+	Pairs     []Pair
+	OnlyLeft  []Entry
+	OnlyRight []Entry
+}
+
+func Compare(left, right Tree, opts MatchOptions) Comparison {
+	rightByIdentity := map[string][]int{}
+	for i, e := range right.Entries {
+		identity := opts.Identity(e.Signal)
+		rightByIdentity[identity] = append(rightByIdentity[identity], i)
+	}
+
+	c := Comparison{}
+	matchedRight := make([]bool, len(right.Entries))
+	fields := opts.comparedFields()
+	for _, l := range left.Entries {
+		identity := opts.Identity(l.Signal)
+		candidates := rightByIdentity[identity]
+		if len(candidates) == 0 {
+			c.OnlyLeft = append(c.OnlyLeft, l)
+			continue
+		}
+
+		idx := candidates[0]
+		rightByIdentity[identity] = candidates[1:]
+		matchedRight[idx] = true
+
+		r := right.Entries[idx]
+		c.Pairs = append(c.Pairs, Pair{Left: l, Right: r, Conflicts: conflicts(l.Signal, r.Signal, fields)})
+	}
+
+	for i, r := range right.Entries {
+		if !matchedRight[i] {
+			c.OnlyRight = append(c.OnlyRight, r)
+		}
+	}
+
+	return c
+}
+
+func conflicts(l, r signal.Signal, fields []string) []Conflict {
+	var result []Conflict
+	for _, field := range fields {
+		if field == FieldMeta {
+			result = append(result, metaConflicts(l.Meta, r.Meta)...)
+			continue
+		}
+		lv, rv := fieldValue(l, field), fieldValue(r, field)
+		if lv != rv {
+			result = append(result, Conflict{Field: field, Left: lv, Right: rv})
+		}
+	}
+	return result
+}
+
+func metaConflicts(l, r signal.Meta) []Conflict {
+	var result []Conflict
+	keys := map[string]struct{}{}
+	for k := range l {
+		keys[k] = struct{}{}
+	}
+	for k := range r {
+		keys[k] = struct{}{}
+	}
+	for k := range keys {
+		if l[k] != r[k] {
+			result = append(result, Conflict{Field: FieldMeta + "." + k, Left: l[k], Right: r[k]})
+		}
+	}
+	sortConflicts(result)
+	return result
+}
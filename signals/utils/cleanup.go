@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+type CleanupOptions struct {
+	SplitTime         irp.Micros `json:"splitTime" doc:"min space that splits frames, us"`
+	SplitProtocol     string     `json:"splitProtocol" doc:"split frames by the gap of this protocol"`
+	KeepFrames        int        `json:"keepFrames" doc:"frames to keep, 0 for all"`
+	TrailingSilence   irp.Micros `json:"trailingSilence" doc:"last gap set to this silence, appended if data ends on a mark, us"`
+	EvenLength        bool       `json:"evenLength" doc:"append a default gap if data ends on a mark"`
+	DropZeroDurations bool       `json:"dropZeroDurations" doc:"drop zero durations from data"`
+}
+
+func (this CleanupOptions) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckNotNegative(this.KeepFrames, "keepFrames")
+		if this.SplitTime != 0 && this.SplitProtocol != "" {
+			errs.Throw(errs.Error("splitTime and splitProtocol are mutually exclusive"))
+		}
+		if this.KeepFrames != 0 && this.SplitTime == 0 && this.SplitProtocol == "" {
+			errs.Throw(errs.Error("keepFrames requires splitTime or splitProtocol"))
+		}
+		if this.SplitProtocol != "" {
+			_, err := irp.GetIrp(strings.ToLower(this.SplitProtocol))
+			errs.ThrowIf(err)
+		}
+	})
+}
+
+func (this CleanupOptions) GetSplitTime() irp.Micros {
+	if this.SplitProtocol != "" {
+		p, err := irp.GetIrp(strings.ToLower(this.SplitProtocol))
+		if err == nil {
+			return p.MinSplitTime()
+		}
+	}
+	return this.SplitTime
+}
+
+func (this CleanupOptions) Transforms() []TransformSignalFn {
+	var trs []TransformSignalFn
+
+	if this.DropZeroDurations {
+		trs = append(trs, rawDataTransform(func(data irp.SignalData) irp.SignalData {
+			return data.DropZeroDurations()
+		}))
+	}
+
+	if this.KeepFrames > 0 {
+		splitTime := this.GetSplitTime()
+		keepFrames := this.KeepFrames
+		trs = append(trs, rawDataTransform(func(data irp.SignalData) irp.SignalData {
+			return data.KeepFrames(splitTime, keepFrames)
+		}))
+	}
+
+	if this.TrailingSilence != 0 {
+		silence := this.TrailingSilence
+		trs = append(trs, rawDataTransform(func(data irp.SignalData) irp.SignalData {
+			return data.WithTrailingSilence(silence)
+		}))
+	}
+
+	if this.EvenLength {
+		trs = append(trs, rawDataTransform(func(data irp.SignalData) irp.SignalData {
+			return data.WithEvenLength()
+		}))
+	}
+
+	return trs
+}
+
+func rawDataTransform(transform func(data irp.SignalData) irp.SignalData) TransformSignalFn {
+	return func(s signal.Signal) (signal.Signal, error) {
+		if s.Protocol != "" || len(s.Data) == 0 {
+			return s, nil
+		}
+		s.Data = transform(s.Data)
+		s.UpdateFingerprint()
+		return s, nil
+	}
+}
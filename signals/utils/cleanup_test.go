@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+)
+
+func Test_Cleanup_OddLengthCapture(t *testing.T) {
+	capture := irp.SignalData{9000, 4500, 560, 1690, 560}
+
+	tests := []struct {
+		name     string
+		options  CleanupOptions
+		expected irp.SignalData
+	}{
+		{"no options", CleanupOptions{}, capture},
+		{"trailing silence", CleanupOptions{TrailingSilence: 40000}, irp.SignalData{9000, 4500, 560, 1690, 560, 40000}},
+		{"even length", CleanupOptions{EvenLength: true}, irp.SignalData{9000, 4500, 560, 1690, 560, irp.DefaultTrailingSilence}},
+		{"both", CleanupOptions{TrailingSilence: 40000, EvenLength: true}, irp.SignalData{9000, 4500, 560, 1690, 560, 40000}},
+	}
+
+	for _, test := range tests {
+		assert.NoError(t, test.options.Validate(), test.name)
+
+		s := signal.Signal{Function: "Power", Frequency: 38000, Data: capture.Clone()}
+		for _, transform := range test.options.Transforms() {
+			var err error
+			s, err = transform(s)
+			assert.NoError(t, err, test.name)
+		}
+
+		assert.Equal(t, test.expected, s.Data, test.name)
+	}
+}
+
+func Test_Cleanup_EvenLengthCapture(t *testing.T) {
+	capture := irp.SignalData{9000, 4500, 560, 100000}
+
+	s, err := CleanupOptions{TrailingSilence: 40000}.Transforms()[0](signal.Signal{Data: capture.Clone()})
+	assert.NoError(t, err)
+	assert.Equal(t, irp.SignalData{9000, 4500, 560, 40000}, s.Data)
+}
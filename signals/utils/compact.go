@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+type CompactOptions struct {
+	MinConfidence         float64       `json:"minConfidence" doc:"min recognition confidence, (0, 1]"`
+	MaxFrequencyDeviation irp.Frequency `json:"maxFrequencyDeviation" doc:"max carrier deviation from the protocol, 0 for any"`
+	ToleranceScale        float64       `json:"toleranceScale" doc:"scale of protocol timing tolerances"`
+	Protocols             []string      `json:"protocols" doc:"protocols to try, all by default"`
+}
+
+func (this CompactOptions) Validate() error {
+	return errs.Catch(func() {
+		if this.MinConfidence <= 0 || this.MinConfidence > 1 {
+			errs.Throw(errs.Errorf("minConfidence must be in (0, 1], got %v", this.MinConfidence))
+		}
+		errs.ThrowCheckPositive(this.ToleranceScale, "toleranceScale")
+		for _, protocol := range this.Protocols {
+			_, err := irp.GetIrp(strings.ToLower(protocol))
+			errs.ThrowIf(err)
+		}
+	})
+}
+
+func (this CompactOptions) Adjust() CompactOptions {
+	if this.MinConfidence == 0 {
+		this.MinConfidence = 1
+	}
+	if this.ToleranceScale == 0 {
+		this.ToleranceScale = 1
+	}
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type CompactResult struct {
+	Recognized  bool             `json:"recognized"`
+	Converted   bool             `json:"converted"`
+	Reason      string           `json:"reason,omitempty"`
+	Recognition *irp.Recognition `json:"recognition,omitempty"`
+	Comparison  *irp.Comparison  `json:"comparison,omitempty"`
+	Confidence  float64          `json:"confidence"`
+}
+
+const (
+	CompactReasonNotRaw             = "not raw"
+	CompactReasonNotRecognized      = "not recognized"
+	CompactReasonProtocolNotAllowed = "protocol not allowed"
+	CompactReasonFrequency          = "frequency deviation"
+	CompactReasonEncodeFailed       = "encode failed"
+	CompactReasonLowConfidence      = "low confidence"
+)
+
+func (this CompactOptions) Compact(s signal.Signal) (signal.Signal, CompactResult) {
+	result := CompactResult{}
+	if s.Protocol != "" || len(s.Data) == 0 {
+		result.Reason = CompactReasonNotRaw
+		return s, result
+	}
+
+	rec, ok := irp.Recognize(s.Data)
+	if !ok {
+		result.Reason = CompactReasonNotRecognized
+		return s, result
+	}
+	result.Recognized = true
+	result.Recognition = &rec
+
+	if !this.isProtocolAllowed(rec.Protocol) {
+		result.Reason = CompactReasonProtocolNotAllowed
+		return s, result
+	}
+
+	p, err := irp.GetIrp(strings.ToLower(rec.Protocol))
+	if err != nil {
+		result.Reason = CompactReasonEncodeFailed
+		return s, result
+	}
+
+	if this.MaxFrequencyDeviation != 0 && absFrequencyDiff(p.Frequency(), s.Frequency) > this.MaxFrequencyDeviation {
+		result.Reason = CompactReasonFrequency
+		return s, result
+	}
+
+	expected, err := p.Decode(rec.Code)
+	if err != nil {
+		result.Reason = CompactReasonEncodeFailed
+		return s, result
+	}
+
+	tolerances := irp.GetTolerances(rec.Protocol).Scaled(this.ToleranceScale)
+	comparison := irp.CompareSignalData(expected, s.Data, rec.Length, tolerances)
+	result.Comparison = &comparison
+	result.Confidence = comparison.Confidence()
+	if result.Confidence < this.MinConfidence {
+		result.Reason = CompactReasonLowConfidence
+		return s, result
+	}
+
+	s.Protocol = rec.Protocol
+	s.Code = rec.Code
+	s.Frequency = p.Frequency()
+	s.Data = expected
+	delete(s.Meta, signal.MetaDutyCycle)
+	s.UpdateFingerprint()
+	result.Converted = true
+
+	return s, result
+}
+
+func (this CompactOptions) isProtocolAllowed(protocol string) bool {
+	if len(this.Protocols) == 0 {
+		return true
+	}
+	for _, allowed := range this.Protocols {
+		if strings.EqualFold(allowed, protocol) {
+			return true
+		}
+	}
+	return false
+}
+
+func absFrequencyDiff(a, b irp.Frequency) irp.Frequency {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
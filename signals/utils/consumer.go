@@ -16,3 +16,21 @@ type ClosableSignalConsumer interface {
 }
 
 type SignalsToFileConsumerSourceFn = func(filePath string) (ClosableSignalConsumer, error)
+
+func NewNopClosingSignalConsumer(origin SignalConsumer) ClosableSignalConsumer {
+	return &nopClosingSignalConsumer{SignalConsumer: origin}
+}
+
+type nopClosingSignalConsumer struct {
+	SignalConsumer
+}
+
+func (this *nopClosingSignalConsumer) Close() error {
+	return nil
+}
+
+type SignalConsumerFn func(s signal.Signal) error
+
+func (this SignalConsumerFn) Consume(s signal.Signal) error {
+	return this(s)
+}
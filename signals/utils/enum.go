@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"path/filepath"
+	"strings"
 
 	"irptools/signals/signal"
 	"irptools/utils/errs"
@@ -12,7 +15,15 @@ import (
 )
 
 func EnumSignals(ctx context.Context, rootPath string, getConsumer SignalsToFileConsumerSourceFn) error {
-	err := fs.EnumFilePathsWithExt(rootPath, ".json", func(filePath string) (res bool, err error) {
+	resolvedPath, _ := ResolveSignalsPath(rootPath)
+	if IsCatalog(resolvedPath) {
+		return enumSignalsStreamFile(ctx, filepath.Join(resolvedPath, CatalogDataFileName), rootPath, getConsumer)
+	}
+	if isSignalsStreamFile(resolvedPath) {
+		return enumSignalsStreamFile(ctx, resolvedPath, rootPath, getConsumer)
+	}
+
+	err := fs.EnumFilePathsWithExt(resolvedPath, ".json", func(filePath string) (res bool, err error) {
 
 		f, err := fs.OpenReadOnlyFile(filePath)
 		if err != nil {
@@ -22,7 +33,7 @@ func EnumSignals(ctx context.Context, rootPath string, getConsumer SignalsToFile
 			err = errs.Join(err, f.Close())
 		}()
 
-		consumer, err := getConsumer(filePath)
+		consumer, err := getConsumer(rootPath + filePath[len(resolvedPath):])
 		if err != nil {
 			return false, errs.Wrap(err)
 		}
@@ -41,6 +52,112 @@ func EnumSignals(ctx context.Context, rootPath string, getConsumer SignalsToFile
 	return errs.Wrap(err)
 }
 
+func enumSignalsStreamFile(ctx context.Context, filePath string, rootPath string, getConsumer SignalsToFileConsumerSourceFn) (err error) {
+	f, err := fs.OpenReadOnlyFile(filePath)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer func() {
+		err = errs.Join(err, f.Close())
+	}()
+
+	var stream io.Reader = f
+	if strings.HasSuffix(strings.ToLower(filePath), ".gz") {
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(f)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		defer func() {
+			err = errs.Join(err, gz.Close())
+		}()
+		stream = gz
+	}
+
+	var consumer ClosableSignalConsumer
+	defer func() {
+		if consumer != nil {
+			err = errs.Join(err, consumer.Close())
+		}
+	}()
+
+	file := ""
+	seen := map[string]struct{}{}
+	decoder := json.NewDecoder(stream)
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		r, err := decodeSignalRecord(decoder)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errs.Wrap(err)
+		}
+
+		if consumer == nil || r.File != file {
+			if consumer != nil {
+				err = consumer.Close()
+				consumer = nil
+				if err != nil {
+					return errs.Wrap(err)
+				}
+			}
+
+			if _, ok := seen[r.File]; ok {
+				return errs.Errorf("records of '%s' are not contiguous", r.File)
+			}
+			seen[r.File] = struct{}{}
+			file = r.File
+
+			consumer, err = getConsumer(filepath.Join(rootPath, filepath.FromSlash(r.File)))
+			if err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		err = consumer.Consume(r.Signal)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+	}
+}
+
+func decodeSignalRecord(decoder *json.Decoder) (SignalRecord, error) {
+	raw := json.RawMessage{}
+	err := decoder.Decode(&raw)
+	if err != nil {
+		return SignalRecord{}, err
+	}
+
+	line := struct {
+		File   string         `json:"file"`
+		Signal *signal.Signal `json:"signal"`
+	}{}
+	err = json.Unmarshal(raw, &line)
+	if err != nil {
+		return SignalRecord{}, errs.Wrap(err)
+	}
+
+	r := SignalRecord{File: line.File}
+	if line.Signal != nil {
+		r.Signal = *line.Signal
+	} else {
+		r.File = SignalsStreamFileName
+		err = json.Unmarshal(raw, &r.Signal)
+		if err != nil {
+			return SignalRecord{}, errs.Wrap(err)
+		}
+	}
+
+	if r.Signal.Fingerprint == "" {
+		r.Signal.UpdateFingerprint()
+	}
+	return r, nil
+}
+
 func EnumStreamSignals(ctx context.Context, stream io.Reader, consumer SignalConsumer) error {
 	decoder := json.NewDecoder(stream)
 	for {
@@ -59,6 +176,9 @@ func EnumStreamSignals(ctx context.Context, stream io.Reader, consumer SignalCon
 			}
 			return errs.Wrap(err)
 		}
+		if s.Fingerprint == "" {
+			s.UpdateFingerprint()
+		}
 
 		err = consumer.Consume(s)
 		if err != nil {
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+	"irptools/utils/misc"
+)
+
+const (
+	LayoutTree    = "tree"
+	LayoutJsonl   = "jsonl"
+	LayoutJsonlGz = "jsonl.gz"
+	LayoutZip     = "zip"
+
+	SignalsStreamFileName = "signals"
+
+	CatalogDataFileName  = "signals.jsonl"
+	CatalogIndexFileName = "signals.idx"
+)
+
+var layoutExts = []string{"." + LayoutZip, "." + LayoutJsonl, "." + LayoutJsonlGz}
+
+func ValidateLayout(layout string) error {
+	switch layout {
+	case "", LayoutTree, LayoutJsonl, LayoutJsonlGz, LayoutZip:
+		return nil
+	}
+	return errs.Errorf("unexpected layout: '%s'", layout)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func NewLayoutSink(layout string, resultPath string, prettyJson bool) (*LayoutSink, error) {
+	sink := &LayoutSink{layout: layout, resultPath: resultPath, prettyJson: prettyJson}
+
+	var err error
+	switch layout {
+	case "", LayoutTree:
+		return sink, nil
+	case LayoutJsonl, LayoutJsonlGz:
+		sink.file, err = createLayoutFile(resultPath + "." + layout)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		var w io.WriteCloser = sink.file
+		if layout == LayoutJsonlGz {
+			sink.gz = gzip.NewWriter(sink.file)
+			w = sink.gz
+		}
+		sink.records = json.NewEncoder(w)
+	case LayoutZip:
+		sink.file, err = createLayoutFile(resultPath + "." + layout)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		sink.zip = zip.NewWriter(sink.file)
+	default:
+		return nil, ValidateLayout(layout)
+	}
+
+	return sink, nil
+}
+
+type SignalRecord struct {
+	File   string        `json:"file"`
+	Signal signal.Signal `json:"signal"`
+}
+
+type LayoutSink struct {
+	layout     string
+	resultPath string
+	prettyJson bool
+	file       *os.File
+	gz         *gzip.Writer
+	records    *json.Encoder
+	zip        *zip.Writer
+}
+
+func (this *LayoutSink) NewConsumer(filePath string) (ClosableSignalConsumer, error) {
+	if this.records == nil && this.zip == nil {
+		return NewJsonFileWriter(filePath, this.prettyJson)
+	}
+
+	relPath, err := filepath.Rel(this.resultPath, filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	relPath = filepath.ToSlash(relPath)
+	if !strings.HasSuffix(relPath, ".json") {
+		relPath = strings.TrimSuffix(relPath, ".") + ".json"
+	}
+
+	if this.records != nil {
+		return NewNopClosingSignalConsumer(SignalConsumerFn(func(s signal.Signal) error {
+			return this.records.Encode(SignalRecord{File: relPath, Signal: s})
+		})), nil
+	}
+
+	w, err := this.zip.CreateHeader(&zip.FileHeader{Name: relPath, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return NewJsonWriter(misc.NopWriteCloser(w), this.prettyJson), nil
+}
+
+func (this *LayoutSink) Close() error {
+	var allErrors []error
+	if this.gz != nil {
+		allErrors = append(allErrors, this.gz.Close())
+	}
+	if this.zip != nil {
+		allErrors = append(allErrors, this.zip.Close())
+	}
+	if this.file != nil {
+		allErrors = append(allErrors, this.file.Close())
+	}
+	return errs.Join(allErrors...)
+}
+
+func createLayoutFile(filePath string) (*os.File, error) {
+	dirPath, _ := filepath.Split(filePath)
+	_, err := fs.EnsureDirExists(dirPath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return fs.CreateWriteOnlyFile(filePath)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func ResolveSignalsPath(rootPath string) (string, bool) {
+	if _, err := os.Stat(rootPath); err == nil {
+		return rootPath, true
+	}
+	for _, ext := range layoutExts {
+		if _, err := os.Stat(rootPath + ext); err == nil {
+			return rootPath + ext, true
+		}
+	}
+	return rootPath, false
+}
+
+func IsCatalog(dirPath string) bool {
+	info, err := os.Stat(filepath.Join(dirPath, CatalogIndexFileName))
+	return err == nil && !info.IsDir()
+}
+
+func isSignalsStreamFile(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	return strings.HasSuffix(lower, "."+LayoutJsonl) || strings.HasSuffix(lower, "."+LayoutJsonlGz)
+}
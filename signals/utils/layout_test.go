@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/fs"
+)
+
+func testLayoutSignal(brand string, function string) signal.Signal {
+	s := signal.Signal{Brand: brand, Function: function, Frequency: 38000, Data: irp.SignalData{9000, 4500, 560, 40000}}
+	s.UpdateFingerprint()
+	return s
+}
+
+var testLayoutFiles = map[string][]signal.Signal{
+	"fz/LG.ir.json":        {testLayoutSignal("LG", "Power"), testLayoutSignal("LG", "Mute")},
+	"fz/Sony/Sony.ir.json": {testLayoutSignal("Sony", "Power")},
+	"raw.ir.json":          {testLayoutSignal("", "Vol+")},
+}
+
+func enumLayoutFiles(t *testing.T, rootPath string) map[string][]signal.Signal {
+	files := map[string][]signal.Signal{}
+	err := EnumSignals(context.Background(), rootPath, func(filePath string) (ClosableSignalConsumer, error) {
+		relPath, err := filepath.Rel(rootPath, filePath)
+		if err != nil {
+			return nil, err
+		}
+		relPath = filepath.ToSlash(relPath)
+		return NewNopClosingSignalConsumer(SignalConsumerFn(func(s signal.Signal) error {
+			files[relPath] = append(files[relPath], s)
+			return nil
+		})), nil
+	})
+	assert.NoError(t, err, rootPath)
+	return files
+}
+
+func Test_Layout_RoundTrip(t *testing.T) {
+	tests := []struct {
+		layout       string
+		resolvedPath string
+	}{
+		{LayoutTree, "result"},
+		{LayoutJsonl, "result.jsonl"},
+		{LayoutJsonlGz, "result.jsonl.gz"},
+		{LayoutZip, "result.zip"},
+	}
+
+	defer func() {
+		assert.NoError(t, fs.CloseArchives())
+	}()
+
+	for _, test := range tests {
+		root := t.TempDir()
+		resultPath := filepath.Join(root, "result")
+
+		sink, err := NewLayoutSink(test.layout, resultPath, false)
+		assert.NoError(t, err, test.layout)
+		for file, signals := range testLayoutFiles {
+			consumer, err := sink.NewConsumer(filepath.Join(resultPath, filepath.FromSlash(file)))
+			assert.NoError(t, err, test.layout)
+			for _, s := range signals {
+				assert.NoError(t, consumer.Consume(s), test.layout)
+			}
+			assert.NoError(t, consumer.Close(), test.layout)
+		}
+		assert.NoError(t, sink.Close(), test.layout)
+
+		resolvedPath, ok := ResolveSignalsPath(resultPath)
+		assert.True(t, ok, test.layout)
+		assert.Equal(t, filepath.Join(root, test.resolvedPath), resolvedPath, test.layout)
+
+		assert.Equal(t, testLayoutFiles, enumLayoutFiles(t, resultPath), test.layout)
+	}
+}
+
+func Test_Layout_ResolveSignalsPath(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "tree"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "both"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "both.zip"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "stream.jsonl.gz"), nil, 0644))
+
+	tests := []struct {
+		path     string
+		resolved string
+		ok       bool
+	}{
+		{"tree", "tree", true},
+		{"both", "both", true},
+		{"stream", "stream.jsonl.gz", true},
+		{"stream.jsonl.gz", "stream.jsonl.gz", true},
+		{"missing", "missing", false},
+	}
+
+	for _, test := range tests {
+		resolved, ok := ResolveSignalsPath(filepath.Join(root, test.path))
+		assert.Equal(t, test.ok, ok, test.path)
+		assert.Equal(t, filepath.Join(root, test.resolved), resolved, test.path)
+	}
+}
+
+func Test_Layout_StreamRecords(t *testing.T) {
+	power := `{"brand":"LG","function":"Power","frequency":38000,"data":[9000,4500,560,40000]}`
+	mute := `{"brand":"LG","function":"Mute","frequency":38000,"data":[9000,4500,560,40000]}`
+
+	tests := []struct {
+		name  string
+		lines []string
+		files map[string][]string
+		fails bool
+	}{
+		{
+			"records",
+			[]string{`{"file":"a.json","signal":` + power + `}`, `{"file":"a.json","signal":` + mute + `}`, `{"file":"b.json","signal":` + power + `}`},
+			map[string][]string{"a.json": {"Power", "Mute"}, "b.json": {"Power"}},
+			false,
+		},
+		{
+			"legacy",
+			[]string{power, mute},
+			map[string][]string{SignalsStreamFileName: {"Power", "Mute"}},
+			false,
+		},
+		{
+			"not contiguous",
+			[]string{`{"file":"a.json","signal":` + power + `}`, `{"file":"b.json","signal":` + power + `}`, `{"file":"a.json","signal":` + mute + `}`},
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		root := t.TempDir()
+		filePath := filepath.Join(root, "result.jsonl")
+		assert.NoError(t, os.WriteFile(filePath, []byte(strings.Join(test.lines, "\n")+"\n"), 0644), test.name)
+
+		files := map[string][]string{}
+		err := EnumSignals(context.Background(), filepath.Join(root, "result"), func(filePath string) (ClosableSignalConsumer, error) {
+			relPath, err := filepath.Rel(filepath.Join(root, "result"), filePath)
+			if err != nil {
+				return nil, err
+			}
+			return NewNopClosingSignalConsumer(SignalConsumerFn(func(s signal.Signal) error {
+				assert.NotEmpty(t, s.Fingerprint, test.name)
+				files[relPath] = append(files[relPath], s.Function)
+				return nil
+			})), nil
+		})
+		if test.fails {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.files, files, test.name)
+	}
+}
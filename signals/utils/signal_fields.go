@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+var DefaultSignalFields = []string{
+	signal.FieldId,
+	signal.FieldBrand,
+	signal.FieldDevice,
+	signal.FieldFunction,
+	signal.FieldProtocol,
+	signal.FieldAddress,
+	signal.FieldCommand,
+	signal.FieldFrequency,
+	signal.FieldDuty,
+	signal.FieldDuration,
+	signal.FieldPulses,
+	signal.FieldData,
+}
+
+type SignalFieldFormatter = func(s *signal.Signal) string
+
+func NewSignalFieldFormatter(field string, dataSeparator string) (SignalFieldFormatter, error) {
+	if key, ok := strings.CutPrefix(field, signal.FieldMetaPrefix); ok {
+		return func(s *signal.Signal) string { return s.Meta[key] }, nil
+	}
+
+	switch field {
+	case signal.FieldId:
+		return func(s *signal.Signal) string { return s.Id }, nil
+	case signal.FieldFingerprint:
+		return func(s *signal.Signal) string { return s.Fingerprint }, nil
+	case signal.FieldSource:
+		return func(s *signal.Signal) string { return s.Source }, nil
+	case signal.FieldBrand:
+		return func(s *signal.Signal) string { return s.Brand }, nil
+	case signal.FieldDevice:
+		return func(s *signal.Signal) string { return s.Device }, nil
+	case signal.FieldModel:
+		return func(s *signal.Signal) string { return s.Model }, nil
+	case signal.FieldFunction:
+		return func(s *signal.Signal) string { return s.Function }, nil
+	case signal.FieldProtocol:
+		return func(s *signal.Signal) string { return s.Protocol }, nil
+	case signal.FieldAddress:
+		return func(s *signal.Signal) string { return formatSignalCode(s, s.Code.Address) }, nil
+	case signal.FieldCommand:
+		return func(s *signal.Signal) string { return formatSignalCode(s, s.Code.Command) }, nil
+	case signal.FieldFrequency:
+		return func(s *signal.Signal) string { return strconv.FormatUint(uint64(s.Frequency), 10) }, nil
+	case signal.FieldDuty:
+		return SignalDutyCycle, nil
+	case signal.FieldDuration:
+		return func(s *signal.Signal) string { return strconv.FormatUint(uint64(s.Data.Duration()), 10) }, nil
+	case signal.FieldPulses:
+		return func(s *signal.Signal) string { return strconv.Itoa(s.Data.Pulses()) }, nil
+	case signal.FieldData:
+		return func(s *signal.Signal) string { return irp.JoinMicrosArr(s.Data, dataSeparator) }, nil
+	}
+
+	return nil, errs.Errorf("unknown signal field: '%s'", field)
+}
+
+func SignalDutyCycle(s *signal.Signal) string {
+	dutyCycle, ok := s.Meta[signal.MetaDutyCycle]
+	if !ok {
+		return signal.DefaultDutyCycle
+	}
+	return dutyCycle
+}
+
+func formatSignalCode(s *signal.Signal, code [4]uint8) string {
+	if s.Protocol == "" {
+		return ""
+	}
+	return irp.FormatHex32(code)
+}
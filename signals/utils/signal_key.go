@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+
+	"irptools/signals/signal"
+)
+
+func SignalContentHash(s signal.Signal) string {
+	key := strings.Join([]string{
+		strings.ToLower(s.Brand),
+		strings.ToLower(s.Device),
+		strings.ToLower(s.Function),
+		signal.FingerprintOf(s),
+	}, "\x00")
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
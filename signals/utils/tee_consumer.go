@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+func NewTeeSignalConsumer(consumers ...ClosableSignalConsumer) ClosableSignalConsumer {
+	if len(consumers) == 1 {
+		return consumers[0]
+	}
+	return &TeeSignalConsumer{consumers: consumers}
+}
+
+type TeeSignalConsumer struct {
+	consumers []ClosableSignalConsumer
+}
+
+func (this *TeeSignalConsumer) Consume(s signal.Signal) error {
+	for _, consumer := range this.consumers {
+		err := consumer.Consume(s)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	return nil
+}
+
+func (this *TeeSignalConsumer) Close() error {
+	allErrors := make([]error, 0, len(this.consumers))
+	for _, consumer := range this.consumers {
+		allErrors = append(allErrors, consumer.Close())
+	}
+	return errs.Join(allErrors...)
+}
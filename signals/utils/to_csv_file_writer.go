@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"encoding/csv"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+)
+
+type CsvFormat struct {
+	Fields        []string
+	Delimiter     rune
+	DataSeparator string
+	SkipHeader    bool
+}
+
+func NewCsvFileWriter(filePath string, format CsvFormat) (*CsvFileWriter, error) {
+	filePath, err := csvFilePath(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	file, err := fs.CreateWriteOnlyFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return NewCsvWriter(file, format)
+}
+
+// appends records to a file created by NewCsvFileWriter, the header is not repeated
+func OpenCsvFileWriter(filePath string, format CsvFormat) (*CsvFileWriter, error) {
+	filePath, err := csvFilePath(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	file, err := fs.OpenAppendOnlyFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	format.SkipHeader = true
+	return NewCsvWriter(file, format)
+}
+
+func csvFilePath(filePath string) (string, error) {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+
+	dirPath, _ := filepath.Split(filePath)
+	_, err = fs.EnsureDirExists(dirPath)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+
+	const csvExt = ".csv"
+	if strings.LastIndex(filePath, csvExt) != len(filePath)-len(csvExt) {
+		if filePath[len(filePath)-1] != '.' {
+			filePath += "."
+		}
+		filePath += "csv"
+	}
+
+	return filePath, nil
+}
+
+func NewCsvWriter(writer io.WriteCloser, format CsvFormat) (*CsvFileWriter, error) {
+	encoder, err := NewCsvEncoder(writer, format)
+	if err != nil {
+		_ = writer.Close()
+		return nil, errs.Wrap(err)
+	}
+
+	return &CsvFileWriter{
+		file:    writer,
+		encoder: encoder,
+	}, nil
+}
+
+type CsvFileWriter struct {
+	file    io.WriteCloser
+	encoder *CsvEncoder
+}
+
+func (this *CsvFileWriter) Consume(signal signal.Signal) error {
+	return this.encoder.Encode(signal)
+}
+
+func (this *CsvFileWriter) Close() error {
+	return errs.Join(this.encoder.Flush(), this.file.Close())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func NewCsvEncoder(writer io.Writer, format CsvFormat) (*CsvEncoder, error) {
+	formatters := make([]SignalFieldFormatter, 0, len(format.Fields))
+	for _, field := range format.Fields {
+		formatter, err := NewSignalFieldFormatter(field, format.DataSeparator)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		formatters = append(formatters, formatter)
+	}
+
+	w := csv.NewWriter(writer)
+	if format.Delimiter != 0 {
+		w.Comma = format.Delimiter
+	}
+
+	if !format.SkipHeader {
+		err := w.Write(format.Fields)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	return &CsvEncoder{
+		w:          w,
+		formatters: formatters,
+		record:     make([]string, len(formatters)),
+	}, nil
+}
+
+type CsvEncoder struct {
+	w          *csv.Writer
+	formatters []SignalFieldFormatter
+	record     []string
+}
+
+func (this *CsvEncoder) Encode(s signal.Signal) error {
+	for i, format := range this.formatters {
+		this.record[i] = format(&s)
+	}
+	return errs.Wrap(this.w.Write(this.record))
+}
+
+func (this *CsvEncoder) Flush() error {
+	this.w.Flush()
+	return errs.Wrap(this.w.Error())
+}
@@ -3,18 +3,32 @@ package utils
 import (
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"irptools/signals/irp"
 	"irptools/signals/signal"
+	"irptools/utils/alg"
 	"irptools/utils/errs"
 	"irptools/utils/fs"
 )
 
+const (
+	IrFileTypeSignals = "IR signals file"
+	IrFileTypeLibrary = "IR library file"
+)
+
 func NewIrFileWriter(filePath string) (*IrFileWriter, error) {
+	return NewIrFileWriterWithType(filePath, IrFileTypeSignals)
+}
+
+func NewIrLibraryFileWriter(filePath string) (*IrFileWriter, error) {
+	return NewIrFileWriterWithType(filePath, IrFileTypeLibrary)
+}
+
+func NewIrFileWriterWithType(filePath string, fileType string) (*IrFileWriter, error) {
 	filePath, err := filepath.Abs(filePath)
 	if err != nil {
 		return nil, errs.Wrap(err)
@@ -39,26 +53,32 @@ func NewIrFileWriter(filePath string) (*IrFileWriter, error) {
 		return nil, errs.Wrap(err)
 	}
 
-	_, err = fmt.Fprintln(file, "Filetype: IR signals file")
+	return NewIrWriter(file, fileType)
+}
+
+func NewIrWriter(writer io.WriteCloser, fileType string) (*IrFileWriter, error) {
+	_, err := fmt.Fprintln(writer, "Filetype:", fileType)
 	if err != nil {
+		_ = writer.Close()
 		return nil, errs.Wrap(err)
 	}
 
-	_, err = fmt.Fprintln(file, "Version: 1")
+	_, err = fmt.Fprintln(writer, "Version: 1")
 	if err != nil {
+		_ = writer.Close()
 		return nil, errs.Wrap(err)
 	}
 
-	encoder := NewIrEncoder(file)
+	encoder := NewIrEncoder(writer)
 
 	return &IrFileWriter{
-		file:    file,
+		file:    writer,
 		encoder: encoder,
 	}, nil
 }
 
 type IrFileWriter struct {
-	file    *os.File
+	file    io.WriteCloser
 	encoder *IrEncoder
 }
 
@@ -84,17 +104,31 @@ func (this *IrEncoder) Encode(s signal.Signal) error {
 
 	lines := []string{}
 	lines = append(lines, "#", " ")
+	if comment, ok := s.Meta[signal.MetaComment]; ok {
+		for _, line := range strings.Split(comment, "\n") {
+			lines = append(lines, "# ", line)
+		}
+	}
 	lines = append(lines, "name: ", s.Function)
 	if s.Protocol != "" {
 		lines = append(lines, "type: ", "parsed")
 		lines = append(lines, "protocol: ", s.Protocol)
-		lines = append(lines, "address: ", this.format4Bytes(s.Code.Address))
-		lines = append(lines, "command: ", this.format4Bytes(s.Code.Command))
+		lines = append(lines, "address: ", irp.FormatHex32(s.Code.Address))
+		lines = append(lines, "command: ", irp.FormatHex32(s.Code.Command))
 	} else {
 		lines = append(lines, "type: ", "raw")
 		lines = append(lines, "frequency: ", strconv.FormatUint(uint64(s.Frequency), 10))
-		lines = append(lines, "duty_cycle: ", "0.330000")
-		lines = append(lines, "data: ", this.formatSignalData(s.Data))
+		lines = append(lines, "duty_cycle: ", SignalDutyCycle(&s))
+		lines = append(lines, "data: ", irp.JoinMicrosArr(s.Data, " "))
+	}
+
+	extraFields := alg.MapKeys(s.Meta)
+	sort.Strings(extraFields)
+	for _, k := range extraFields {
+		if k == signal.MetaComment || k == signal.MetaDutyCycle {
+			continue
+		}
+		lines = append(lines, k+": ", s.Meta[k])
 	}
 
 	for i := 0; i < len(lines); i += 2 {
@@ -110,21 +144,3 @@ func (this *IrEncoder) Encode(s signal.Signal) error {
 
 	return nil
 }
-
-func (this *IrEncoder) formatSignalData(data irp.SignalData) string {
-	str := fmt.Sprintf("%v", data)
-	str = strings.TrimLeft(str, "[")
-	str = strings.TrimRight(str, "]")
-	str = strings.ReplaceAll(str, ",", " ")
-	return str
-}
-
-func (this *IrEncoder) format4Bytes(data [4]uint8) string {
-	lines := [...]string{
-		fmt.Sprintf("%02X", data[0]),
-		fmt.Sprintf("%02X", data[1]),
-		fmt.Sprintf("%02X", data[2]),
-		fmt.Sprintf("%02X", data[3]),
-	}
-	return strings.Join(lines[:], " ")
-}
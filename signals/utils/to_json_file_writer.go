@@ -2,7 +2,7 @@ package utils
 
 import (
 	"encoding/json"
-	"os"
+	"io"
 	"path/filepath"
 	"strings"
 
@@ -36,19 +36,23 @@ func NewJsonFileWriter(filePath string, prettyJson bool) (*JsonFileWriter, error
 		return nil, errs.Wrap(err)
 	}
 
-	encoder := json.NewEncoder(file)
+	return NewJsonWriter(file, prettyJson), nil
+}
+
+func NewJsonWriter(writer io.WriteCloser, prettyJson bool) *JsonFileWriter {
+	encoder := json.NewEncoder(writer)
 	if prettyJson {
 		encoder.SetIndent("", "  ")
 	}
 
 	return &JsonFileWriter{
-		file:    file,
+		file:    writer,
 		encoder: encoder,
-	}, nil
+	}
 }
 
 type JsonFileWriter struct {
-	file    *os.File
+	file    io.WriteCloser
 	encoder *json.Encoder
 }
 
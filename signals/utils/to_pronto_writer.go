@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+const (
+	prontoClockMicros     = 0.241246
+	prontoDefaultFreq     = 38000
+	prontoTrailingSpaceUs = 10000
+)
+
+func NewProntoWriter(writer io.WriteCloser) *ProntoFileWriter {
+	return &ProntoFileWriter{file: writer}
+}
+
+type ProntoFileWriter struct {
+	file io.WriteCloser
+}
+
+func (this *ProntoFileWriter) Consume(s signal.Signal) error {
+	code, err := Pronto(s)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	_, err = fmt.Fprintf(this.file, "# %s / %s / %s\n%s\n", s.Brand, s.Device, s.Function, code)
+	return errs.Wrap(err)
+}
+
+func (this *ProntoFileWriter) Close() error {
+	return this.file.Close()
+}
+
+func Pronto(s signal.Signal) (string, error) {
+	s, err := ExpandData(s)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	if len(s.Data) == 0 {
+		return "", errs.Errorf("signal without data: '%s'", s.Function)
+	}
+
+	freq := float64(s.Frequency)
+	if freq == 0 {
+		freq = prontoDefaultFreq
+	}
+	freqCode := uint(math.Round(1000000 / (freq * prontoClockMicros)))
+	unitMicros := float64(freqCode) * prontoClockMicros
+
+	data := append(irp.SignalData{}, s.Data...)
+	if len(data)%2 != 0 {
+		data.Add(prontoTrailingSpaceUs)
+	}
+
+	words := make([]string, 0, len(data)+4)
+	words = append(words, "0000", fmt.Sprintf("%04X", freqCode), fmt.Sprintf("%04X", len(data)/2), "0000")
+	for _, d := range data {
+		units := max(1, uint(math.Round(float64(d)/unitMicros)))
+		words = append(words, fmt.Sprintf("%04X", min(units, 0xFFFF)))
+	}
+	return strings.Join(words, " "), nil
+}
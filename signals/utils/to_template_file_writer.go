@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+)
+
+const (
+	TemplateHeader = "header"
+	TemplateSignal = "signal"
+	TemplateFooter = "footer"
+)
+
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"hex":     irp.FormatHex32,
+		"hexByte": func(b uint8) string { return fmt.Sprintf("%02X", b) },
+		"timings": func(separator string, data irp.SignalData) string { return irp.JoinMicrosArr(data, separator) },
+		"pronto":  Pronto,
+		"expand":  ExpandData,
+		"lower":   strings.ToLower,
+		"upper":   strings.ToUpper,
+		"quote":   strconv.Quote,
+		"xml": func(str string) (string, error) {
+			b := strings.Builder{}
+			err := xml.EscapeText(&b, []byte(str))
+			return b.String(), err
+		},
+	}
+}
+
+func LoadTemplate(filePath string) (*template.Template, error) {
+	data, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	t, err := template.New(filepath.Base(filePath)).Funcs(TemplateFuncs()).Parse(string(data))
+	if err != nil {
+		return nil, errs.Errorf("failed to parse template: %w", err)
+	}
+
+	if t.Lookup(TemplateSignal) == nil {
+		return nil, errs.Errorf("template without '%s' section: '%s'", TemplateSignal, filePath)
+	}
+
+	return t, nil
+}
+
+// Index is the position of the signal in the file, in the footer it is the number of rendered signals
+type TemplateData struct {
+	File  string
+	Name  string
+	Index int
+	signal.Signal
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func NewTemplateFileWriter(filePath string, ext string, t *template.Template) (*TemplateFileWriter, error) {
+	filePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	dirPath, _ := filepath.Split(filePath)
+	_, err = fs.EnsureDirExists(dirPath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	ext = strings.TrimPrefix(ext, ".")
+	if ext != "" && !strings.HasSuffix(filePath, "."+ext) {
+		filePath = strings.TrimSuffix(filePath, ".") + "." + ext
+	}
+
+	file, err := fs.CreateWriteOnlyFile(filePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return NewTemplateWriter(file, filePath, t)
+}
+
+func NewTemplateWriter(writer io.WriteCloser, filePath string, t *template.Template) (*TemplateFileWriter, error) {
+	_, fileName := filepath.Split(filePath)
+	this := &TemplateFileWriter{
+		file:     writer,
+		template: t,
+		data: TemplateData{
+			File: filePath,
+			Name: strings.TrimSuffix(fileName, filepath.Ext(fileName)),
+		},
+	}
+
+	err := this.execute(TemplateHeader)
+	if err != nil {
+		_ = writer.Close()
+		return nil, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+type TemplateFileWriter struct {
+	file     io.WriteCloser
+	template *template.Template
+	data     TemplateData
+}
+
+func (this *TemplateFileWriter) Consume(s signal.Signal) error {
+	this.data.Signal = s
+	err := this.execute(TemplateSignal)
+	this.data.Index++
+	return err
+}
+
+func (this *TemplateFileWriter) Close() error {
+	this.data.Signal = signal.Signal{}
+	return errs.Join(this.execute(TemplateFooter), this.file.Close())
+}
+
+func (this *TemplateFileWriter) execute(name string) error {
+	if this.template.Lookup(name) == nil {
+		return nil
+	}
+
+	err := this.template.ExecuteTemplate(this.file, name, this.data)
+	if err != nil {
+		return errs.Errorf("failed to render '%s': %w", name, err)
+	}
+	return nil
+}
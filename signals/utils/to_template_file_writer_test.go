@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/misc"
+)
+
+func testTemplateSignals() []signal.Signal {
+	return []signal.Signal{
+		{Brand: "LG", Device: "TV", Function: "Power", Protocol: "NEC", Code: irp.SignalCode{Address: [4]uint8{0x04}, Command: [4]uint8{0x08}}},
+		{Brand: "Acme & Co", Device: "Fan", Function: "Speed <1>", Frequency: 38000, Data: irp.SignalData{9000, 4500, 560, 40000}},
+	}
+}
+
+func renderTemplate(t *testing.T, text string, signals []signal.Signal) (string, error) {
+	tmpl, err := template.New("test").Funcs(TemplateFuncs()).Parse(text)
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	writer, err := NewTemplateWriter(misc.NopWriteCloser(buf), filepath.Join("out", "remotes.txt"), tmpl)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range signals {
+		err = writer.Consume(s)
+		if err != nil {
+			return "", err
+		}
+	}
+	err = writer.Close()
+	return buf.String(), err
+}
+
+func Test_Template_Funcs(t *testing.T) {
+	raw := testTemplateSignals()[1]
+	parsed := testTemplateSignals()[0]
+
+	tests := []struct {
+		name     string
+		text     string
+		s        signal.Signal
+		expected string
+		isErr    bool
+	}{
+		{"hex", `{{ hex .Code.Address }}`, parsed, "04 00 00 00", false},
+		{"hexByte", `{{ hexByte (index .Code.Command 0) }}`, parsed, "08", false},
+		{"timings", `{{ timings " " .Data }}`, raw, "9000 4500 560 40000", false},
+		{"timings of parsed", `{{ timings "," .Data }}`, parsed, "", false},
+		{"pronto", `{{ pronto .Signal }}`, raw, "0000 006D 0002 0000 0156 00AB 0015 05F1", false},
+		{"pronto of parsed", `{{ $p := pronto .Signal }}{{ slice $p 0 14 }}`, parsed, "0000 006D 0024", false},
+		{"expand", `{{ $s := expand .Signal }}{{ len $s.Data }} {{ $s.Frequency }}`, parsed, "72 38000", false},
+		{"expand raw", `{{ $s := expand .Signal }}{{ timings "," $s.Data }}`, raw, "9000,4500,560,40000", false},
+		{"expand unknown", `{{ expand .Signal }}`, signal.Signal{Protocol: "Unknown"}, "", true},
+		{"pronto without data", `{{ pronto .Signal }}`, signal.Signal{Function: "Empty"}, "", true},
+		{"case", `{{ lower .Brand }} {{ upper .Function }}`, parsed, "lg POWER", false},
+		{"quote", `{{ quote .Function }}`, raw, `"Speed <1>"`, false},
+		{"xml", `{{ xml .Brand }} {{ xml .Function }}`, raw, "Acme &amp; Co Speed &lt;1&gt;", false},
+	}
+
+	for _, test := range tests {
+		actual, err := renderTemplate(t, `{{ define "signal" }}`+test.text+`{{ end }}`, []signal.Signal{test.s})
+		if test.isErr {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, actual, test.name)
+	}
+}
+
+func Test_Template_Sections(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			"all sections",
+			`{{ define "header" }}[{{ .Name }} {{ .File }} {{ .Index }}]{{ end }}` +
+				`{{ define "signal" }}({{ .Index }} {{ .Function }}){{ end }}` +
+				`{{ define "footer" }}[{{ .Index }} {{ .Function }}]{{ end }}`,
+			"[remotes " + filepath.Join("out", "remotes.txt") + " 0](0 Power)(1 Speed <1>)[2 ]",
+		},
+		{
+			"signal only",
+			`{{ define "signal" }}{{ .Brand }};{{ end }}`,
+			"LG;Acme & Co;",
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := renderTemplate(t, test.text, testTemplateSignals())
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, actual, test.name)
+	}
+
+	actual, err := renderTemplate(t, `{{ define "header" }}h{{ end }}{{ define "footer" }}f{{ end }}{{ define "signal" }}s{{ end }}`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "hf", actual)
+}
+
+func Test_Template_LoadTemplate(t *testing.T) {
+	dirPath := t.TempDir()
+
+	noSignal := filepath.Join(dirPath, "no_signal.tmpl")
+	assert.NoError(t, os.WriteFile(noSignal, []byte(`{{ define "header" }}h{{ end }}`), 0644))
+	_, err := LoadTemplate(noSignal)
+	assert.Error(t, err)
+
+	unknownFunc := filepath.Join(dirPath, "unknown_func.tmpl")
+	assert.NoError(t, os.WriteFile(unknownFunc, []byte(`{{ define "signal" }}{{ base64 .Brand }}{{ end }}`), 0644))
+	_, err = LoadTemplate(unknownFunc)
+	assert.Error(t, err)
+}
+
+func Test_Template_VendorXmlGolden(t *testing.T) {
+	tmpl, err := LoadTemplate(filepath.Join("..", "..", "sample", "templates", "vendor.xml.tmpl"))
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	writer, err := NewTemplateWriter(misc.NopWriteCloser(buf), "codes.xml", tmpl)
+	assert.NoError(t, err)
+	for _, s := range testTemplateSignals() {
+		assert.NoError(t, writer.Consume(s))
+	}
+	assert.NoError(t, writer.Close())
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "vendor.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), buf.String())
+}
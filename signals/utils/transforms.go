@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/utils/errs"
+)
+
+func DropSourceField(s signal.Signal) (signal.Signal, error) {
+	s.Source = ""
+	return s, nil
+}
+
+func FieldsToLower(s signal.Signal) (signal.Signal, error) {
+	toLower := func(str *string) {
+		*str = strings.ToLower(*str)
+	}
+	toLower(&s.Source)
+	toLower(&s.Brand)
+	toLower(&s.Device)
+	toLower(&s.Model)
+	toLower(&s.Function)
+	toLower(&s.Protocol)
+	return s, nil
+}
+
+func ExpandData(s signal.Signal) (signal.Signal, error) {
+	if s.Protocol == "" || len(s.Data) != 0 {
+		return s, nil
+	}
+
+	p, err := irp.GetIrp(strings.ToLower(s.Protocol))
+	if err != nil {
+		return s, errs.Wrap(err)
+	}
+
+	s.Data, err = p.Decode(s.Code)
+	if err != nil {
+		return s, errs.Errorf("failed to decode '%s' signal: %w", s.Protocol, err)
+	}
+
+	if s.Frequency == 0 {
+		s.Frequency = p.Frequency()
+	}
+
+	return s, nil
+}
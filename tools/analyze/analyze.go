@@ -0,0 +1,118 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"irptools/signals/analyze"
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/fs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "ANALYZE", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	groups, err := execAnalyze(ctx, cfg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	summary, err := storeGroups(filepath.Join(cfg.Target.Folder.Path, "result"), groups)
+	if err != nil {
+		return errs.Errorf("failed to store groups: %w", err)
+	}
+
+	err = utils.StoreJson(filepath.Join(cfg.Target.Folder.Path, "summary.json"), summary)
+	if err != nil {
+		return errs.Errorf("failed to store summary: %w", err)
+	}
+
+	for _, item := range summary {
+		l.I("%4d: %-20s %s", item.Signals, item.Modulation, item.Irp)
+	}
+
+	return nil
+}
+
+func execAnalyze(ctx context.Context, cfg Config) ([]analyze.Group, error) {
+	analyzer := analyze.NewAnalyzer(cfg.Analyze.Options)
+	filtering := signalutils.NewFilteringSignalConsumer(signalutils.NewNopClosingSignalConsumer(analyzer),
+		func(s signal.Signal) (bool, error) {
+			return len(s.Data) != 0 && (s.Protocol == "" || cfg.Analyze.IncludeParsed), nil
+		})
+
+	err := signalutils.EnumSignals(ctx, cfg.Source, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(filtering), nil
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	var groups []analyze.Group
+	for _, g := range analyzer.Groups() {
+		if len(g.Signals) >= cfg.Analyze.MinGroupSize {
+			groups = append(groups, g)
+		}
+	}
+
+	return groups, nil
+}
+
+type summaryItem struct {
+	File       string             `json:"file"`
+	Key        string             `json:"key"`
+	Irp        string             `json:"irp"`
+	Modulation analyze.Modulation `json:"modulation"`
+	Signals    int                `json:"signals"`
+	Constant   string             `json:"constant"`
+}
+
+func storeGroups(folderPath string, groups []analyze.Group) ([]summaryItem, error) {
+	_, err := fs.EnsureDirExists(folderPath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	summary := make([]summaryItem, 0, len(groups))
+	for i, g := range groups {
+		fileName := fmt.Sprintf("group_%03d.json", i+1)
+		err = utils.StoreJson(filepath.Join(folderPath, fileName), g)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+
+		summary = append(summary, summaryItem{
+			File:       fileName,
+			Key:        g.Key,
+			Irp:        g.Irp,
+			Modulation: g.Modulation,
+			Signals:    len(g.Signals),
+			Constant:   g.Constant,
+		})
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,93 @@
+package analyze
+
+import (
+	"path/filepath"
+
+	"irptools/signals/analyze"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Source  string        `json:"source" doc:"folder with parsed signals"`
+	Target  TargetConfig  `json:"target" doc:"where to write the analysis"`
+	Analyze AnalyzeConfig `json:"analyze" doc:"analysis options"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckValid(this.Analyze, "analyze")
+		errs.ThrowCheckRequiredString(this.Source, "source")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Source))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Analyze = this.Analyze.Adjust()
+
+	this.Source, err = filepath.Abs(this.Source)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder utils.TargetFolder `json:"folder" doc:"output folder"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type AnalyzeConfig struct {
+	Options       analyze.Options `json:"options" doc:"pulse analysis options"`
+	IncludeParsed bool            `json:"includeParsed" doc:"also analyze signals that already have a protocol"`
+	MinGroupSize  int             `json:"minGroupSize" doc:"skip groups with fewer signals"`
+}
+
+func (this AnalyzeConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Options, "options")
+		errs.ThrowCheckNotNegative(this.MinGroupSize, "minGroupSize")
+	})
+}
+
+func (this AnalyzeConfig) Adjust() AnalyzeConfig {
+	this.Options = this.Options.Adjust()
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,141 @@
+package compact
+
+import (
+	"context"
+	"path/filepath"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "COMPACT", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	execCfg := cfg
+	execCfg.Target.Folder = execCfg.Target.Folder.Join("result")
+	r, err := execCompact(ctx, execCfg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = utils.StoreJson(filepath.Join(cfg.Target.Folder.Path, "report.json"), r)
+	if err != nil {
+		return errs.Errorf("failed to store report: %w", err)
+	}
+
+	l.I("raw: %v; recognized: %v; converted: %v; rejected: %v", r.Raw, r.Recognized, r.Converted, r.Rejected)
+
+	return nil
+}
+
+func execCompact(ctx context.Context, cfg Config) (*report, error) {
+	r := &report{ByProtocol: map[string]int{}}
+
+	compact := func(s signal.Signal) (signal.Signal, error) {
+		compacted, result := cfg.Compact.Compact(s)
+		r.add(s, result)
+		return compacted, nil
+	}
+
+	getConsumer := func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		writer, err := signalutils.NewJsonFileWriter(filePath, cfg.Target.PrettyJsonPrint)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		return signalutils.NewTransformingSignalConsumer(writer, []signalutils.TransformSignalFn{compact}), nil
+	}
+
+	getTargetFilePath := signalutils.RepeatSourceTreeTargetFilePathStrategy(cfg.Source, cfg.Target.Folder.Path)
+	factory := signalutils.NewSignalsToFileConsumersFactory(getConsumer, getTargetFilePath)
+	err := signalutils.EnumSignals(ctx, cfg.Source, factory.NewConsumer)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return r, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type report struct {
+	Total      int            `json:"total"`
+	Raw        int            `json:"raw"`
+	Recognized int            `json:"recognized"`
+	Converted  int            `json:"converted"`
+	Rejected   int            `json:"rejected"`
+	ByProtocol map[string]int `json:"byProtocol"`
+	Entries    []reportEntry  `json:"entries"`
+}
+
+type reportEntry struct {
+	Source       string          `json:"source"`
+	Id           string          `json:"id"`
+	Function     string          `json:"function"`
+	Protocol     string          `json:"protocol"`
+	Address      string          `json:"address"`
+	Command      string          `json:"command"`
+	Converted    bool            `json:"converted"`
+	Reason       string          `json:"reason,omitempty"`
+	Confidence   float64         `json:"confidence"`
+	MaxDeviation irp.Micros      `json:"maxDeviation"`
+	Deviations   []irp.Deviation `json:"deviations,omitempty"`
+}
+
+func (this *report) add(s signal.Signal, result signalutils.CompactResult) {
+	this.Total++
+	if result.Reason == signalutils.CompactReasonNotRaw {
+		return
+	}
+
+	this.Raw++
+	if !result.Recognized {
+		return
+	}
+
+	this.Recognized++
+	if result.Converted {
+		this.Converted++
+		this.ByProtocol[result.Recognition.Protocol]++
+	} else {
+		this.Rejected++
+	}
+
+	entry := reportEntry{
+		Source:     s.Source,
+		Id:         s.Id,
+		Function:   s.Function,
+		Protocol:   result.Recognition.Protocol,
+		Address:    irp.FormatHex32(result.Recognition.Code.Address),
+		Command:    irp.FormatHex32(result.Recognition.Code.Command),
+		Converted:  result.Converted,
+		Reason:     result.Reason,
+		Confidence: result.Confidence,
+	}
+	if result.Comparison != nil {
+		entry.MaxDeviation = result.Comparison.MaxDeviation
+		entry.Deviations = result.Comparison.Deviations
+	}
+	this.Entries = append(this.Entries, entry)
+}
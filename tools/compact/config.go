@@ -0,0 +1,74 @@
+package compact
+
+import (
+	"path/filepath"
+
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Source  string                     `json:"source" doc:"folder with parsed signals"`
+	Target  TargetConfig               `json:"target" doc:"where to write compacted signals"`
+	Compact signalutils.CompactOptions `json:"compact" doc:"raw to protocol compaction options"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckValid(this.Compact, "compact")
+		errs.ThrowCheckRequiredString(this.Source, "source")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Source))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Compact = this.Compact.Adjust()
+
+	this.Source, err = filepath.Abs(this.Source)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder          utils.TargetFolder `json:"folder" doc:"output folder"`
+	PrettyJsonPrint bool               `json:"prettyJsonPrint" doc:"indent json output"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
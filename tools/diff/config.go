@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"path/filepath"
+
+	"irptools/signals/tree"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Left   string            `json:"left" doc:"left signals folder"`
+	Right  string            `json:"right" doc:"right signals folder"`
+	Target TargetConfig      `json:"target" doc:"where to write the diff"`
+	Match  tree.MatchOptions `json:"match" doc:"how signals are matched"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckValid(this.Match, "match")
+		errs.ThrowCheckRequiredString(this.Left, "left")
+		errs.ThrowCheckRequiredString(this.Right, "right")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Left))
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Right))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Match = this.Match.Adjust()
+
+	this.Left, err = filepath.Abs(this.Left)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Right, err = filepath.Abs(this.Right)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder utils.TargetFolder `json:"folder" doc:"output folder"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,56 @@
+package diff
+
+import (
+	"context"
+	"path/filepath"
+
+	"irptools/signals/tree"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "DIFF", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("left   <-: %s", cfg.Left)
+	l.I("right  <-: %s", cfg.Right)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	left, err := tree.Load(ctx, cfg.Left)
+	if err != nil {
+		return errs.Errorf("failed to load left tree: %w", err)
+	}
+
+	right, err := tree.Load(ctx, cfg.Right)
+	if err != nil {
+		return errs.Errorf("failed to load right tree: %w", err)
+	}
+
+	c := tree.Compare(left, right, cfg.Match)
+	r := tree.NewReport(left, right, c, true)
+
+	err = utils.StoreJson(filepath.Join(cfg.Target.Folder.Path, "diff.json"), r)
+	if err != nil {
+		return errs.Errorf("failed to store diff: %w", err)
+	}
+
+	l.I("matched: %v; conflicting: %v; only left: %v; only right: %v", r.Matched, r.Conflicting, r.OnlyLeft, r.OnlyRight)
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package export_csv
+
+import (
+	"path/filepath"
+	"unicode/utf8"
+
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Source string       `json:"source" doc:"folder with parsed signals"`
+	Target TargetConfig `json:"target" doc:"where to write csv files"`
+	Format FormatConfig `json:"format" doc:"csv format options"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckValid(this.Format, "format")
+		errs.ThrowCheckRequiredString(this.Source, "source")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Source))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Format = this.Format.Adjust()
+
+	this.Source, err = filepath.Abs(this.Source)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	SplitByNone   = ""
+	SplitByBrand  = "brand"
+	SplitByDevice = "device"
+)
+
+type TargetConfig struct {
+	Folder   utils.TargetFolder `json:"folder" doc:"output folder"`
+	FileName string             `json:"fileName" doc:"csv file name"`
+	SplitBy  string             `json:"splitBy" doc:"split csv files by: none, brand or device"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+		errs.ThrowCheckRequiredString(this.FileName, "fileName")
+		switch this.SplitBy {
+		case SplitByNone, SplitByBrand, SplitByDevice:
+		default:
+			errs.Throw(errs.Errorf("unexpected splitBy: '%s'", this.SplitBy))
+		}
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	if this.FileName == "" {
+		this.FileName = "signals"
+	}
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type FormatConfig struct {
+	Columns       []string `json:"columns" doc:"columns to write, all by default"`
+	Delimiter     string   `json:"delimiter" doc:"column delimiter, comma by default"`
+	DataSeparator string   `json:"dataSeparator" doc:"separator of raw data values"`
+}
+
+func (this FormatConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckNotZero(len(this.Columns), "len(columns)")
+		errs.ThrowCheckRequiredString(this.DataSeparator, "dataSeparator")
+		if utf8.RuneCountInString(this.Delimiter) != 1 {
+			errs.Throw(errs.Errorf("delimiter has to be a single character: '%s'", this.Delimiter))
+		}
+		for _, column := range this.Columns {
+			_, err := signalutils.NewSignalFieldFormatter(column, this.DataSeparator)
+			errs.ThrowIf(err)
+		}
+	})
+}
+
+func (this FormatConfig) Adjust() FormatConfig {
+	if len(this.Columns) == 0 {
+		this.Columns = signalutils.DefaultSignalFields
+	}
+	if this.Delimiter == "" {
+		this.Delimiter = ","
+	}
+	if this.DataSeparator == "" {
+		this.DataSeparator = ","
+	}
+	return this
+}
+
+func (this FormatConfig) CsvFormat() signalutils.CsvFormat {
+	delimiter, _ := utf8.DecodeRuneInString(this.Delimiter)
+	return signalutils.CsvFormat{
+		Fields:        this.Columns,
+		Delimiter:     delimiter,
+		DataSeparator: this.DataSeparator,
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
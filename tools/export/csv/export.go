@@ -0,0 +1,172 @@
+package export_csv
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "EXPORT CSV", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	execCfg := cfg
+	execCfg.Target.Folder = execCfg.Target.Folder.Join("result")
+	err = execExportCsv(ctx, execCfg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+func execExportCsv(ctx context.Context, cfg Config) (err error) {
+	sink := NewSink(cfg.Target.Folder.Path, cfg.Target.FileName, cfg.Target.SplitBy, cfg.Format)
+
+	defer func() {
+		err = errs.Join(err, sink.Close())
+	}()
+
+	err = signalutils.EnumSignals(ctx, cfg.Source, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(sink), nil
+	})
+
+	logs.L(ctx).I("files: %v", sink.Files())
+
+	return errs.Wrap(err)
+}
+
+func getSplitKeyFn(splitBy string) func(s signal.Signal) string {
+	switch splitBy {
+	case SplitByBrand:
+		return func(s signal.Signal) string { return s.Brand }
+	case SplitByDevice:
+		return func(s signal.Signal) string { return s.Device }
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const maxOpenFiles = 64
+
+func NewSink(dirPath string, fileName string, splitBy string, format FormatConfig) *Sink {
+	return &Sink{
+		dirPath:  dirPath,
+		fileName: fileName,
+		format:   format.CsvFormat(),
+		splitBy:  getSplitKeyFn(splitBy),
+		files:    map[string]string{},
+		writers:  map[string]*signalutils.CsvFileWriter{},
+	}
+}
+
+// split keys that map to the same file name, case-insensitively, share the first file created for them;
+// at most maxOpenFiles files are open at once, evicted files are reopened for appending
+type Sink struct {
+	dirPath  string
+	fileName string
+	format   signalutils.CsvFormat
+	splitBy  func(s signal.Signal) string
+	files    map[string]string
+	writers  map[string]*signalutils.CsvFileWriter
+	opened   []string
+}
+
+func (this *Sink) Consume(s signal.Signal) error {
+	fileName := this.fileName
+	if this.splitBy != nil {
+		fileName = toFileName(this.splitBy(s))
+	}
+
+	writer, err := this.getWriter(fileName)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return errs.Wrap(writer.Consume(s))
+}
+
+func (this *Sink) getWriter(fileName string) (*signalutils.CsvFileWriter, error) {
+	key := strings.ToLower(fileName)
+	if writer, ok := this.writers[key]; ok {
+		return writer, nil
+	}
+
+	if len(this.opened) >= maxOpenFiles {
+		evicted := this.opened[0]
+		this.opened = this.opened[1:]
+		err := this.writers[evicted].Close()
+		delete(this.writers, evicted)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	openWriter := signalutils.OpenCsvFileWriter
+	if created, ok := this.files[key]; ok {
+		fileName = created
+	} else {
+		openWriter = signalutils.NewCsvFileWriter
+	}
+
+	writer, err := openWriter(filepath.Join(this.dirPath, fileName), this.format)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	this.files[key] = fileName
+
+	this.writers[key] = writer
+	this.opened = append(this.opened, key)
+	return writer, nil
+}
+
+func (this *Sink) Files() int {
+	return len(this.files)
+}
+
+func (this *Sink) Close() error {
+	allErrors := make([]error, 0)
+	for _, key := range this.opened {
+		allErrors = append(allErrors, this.writers[key].Close())
+	}
+	this.writers = map[string]*signalutils.CsvFileWriter{}
+	this.opened = nil
+	return errs.Join(allErrors...)
+}
+
+func toFileName(key string) string {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "_unknown_"
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
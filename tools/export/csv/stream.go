@@ -0,0 +1,46 @@
+package export_csv
+
+import (
+	"context"
+	"io"
+
+	signalutils "irptools/signals/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+func LoadStreamConfig(filePath string, overrides []misc.ConfigOverride) (StreamConfig, error) {
+	return misc.LoadJsonConfigFromFileOrEmpty(filePath, overrides, func(cfg StreamConfig) (StreamConfig, error) {
+		cfg.Format = cfg.Format.Adjust()
+		return cfg, nil
+	})
+}
+
+type StreamConfig struct {
+	Format FormatConfig `json:"format" doc:"csv format options"`
+}
+
+func (this StreamConfig) Validate() error {
+	return errs.Catch(func() {
+		if len(this.Format.Columns) != 0 {
+			errs.ThrowCheckValid(this.Format, "format")
+		}
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func Stream(ctx context.Context, cfg StreamConfig, in io.Reader, out io.Writer) error {
+	err := errs.CheckValid(cfg.Format, "format")
+	if err != nil {
+		return err
+	}
+
+	writer, err := signalutils.NewCsvWriter(misc.NopWriteCloser(out), cfg.Format.CsvFormat())
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = signalutils.EnumStreamSignals(ctx, in, writer)
+	return errs.Join(errs.Wrap(err), writer.Close())
+}
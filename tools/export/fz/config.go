@@ -10,8 +10,8 @@ import (
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func LoadConfig(filePath string) (Config, error) {
-	return misc.LoadJsonConfigFromFile(filePath, func(cfg Config) (Config, error) {
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
 		return cfg.Adjust()
 	})
 }
@@ -19,8 +19,8 @@ func LoadConfig(filePath string) (Config, error) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type Config struct {
-	Source string       `json:"source"`
-	Target TargetConfig `json:"target"`
+	Source string       `json:"source" doc:"folder with parsed signals"`
+	Target TargetConfig `json:"target" doc:"where to write .ir files"`
 }
 
 func (this Config) Validate() error {
@@ -50,8 +50,8 @@ func (this Config) Adjust() (Config, error) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type TargetConfig struct {
-	Folder      utils.TargetFolder `json:"folder"`
-	ToOneFolder bool               `json:"toOneFolder"`
+	Folder      utils.TargetFolder `json:"folder" doc:"output folder"`
+	ToOneFolder bool               `json:"toOneFolder" doc:"flatten the output into one folder"`
 }
 
 func (this TargetConfig) Validate() error {
@@ -0,0 +1,35 @@
+package export_fz
+
+import (
+	"context"
+	"io"
+
+	signalutils "irptools/signals/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+func LoadStreamConfig(filePath string, overrides []misc.ConfigOverride) (StreamConfig, error) {
+	return misc.LoadJsonConfigFromFileOrEmpty(filePath, overrides, func(cfg StreamConfig) (StreamConfig, error) {
+		return cfg, nil
+	})
+}
+
+type StreamConfig struct {
+}
+
+func (this StreamConfig) Validate() error {
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func Stream(ctx context.Context, cfg StreamConfig, in io.Reader, out io.Writer) error {
+	writer, err := signalutils.NewIrWriter(misc.NopWriteCloser(out), signalutils.IrFileTypeSignals)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = signalutils.EnumStreamSignals(ctx, in, writer)
+	return errs.Join(errs.Wrap(err), writer.Close())
+}
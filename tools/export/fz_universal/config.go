@@ -0,0 +1,115 @@
+package export_fz_universal
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Source  string        `json:"source" doc:"folder with parsed signals"`
+	Target  TargetConfig  `json:"target" doc:"where to write the library"`
+	Library LibraryConfig `json:"library" doc:"universal library options"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckValid(this.Library, "library")
+		errs.ThrowCheckRequiredString(this.Source, "source")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Source))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Source, err = filepath.Abs(this.Source)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder   utils.TargetFolder `json:"folder" doc:"output folder"`
+	FileName string             `json:"fileName" doc:"library file name"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+		errs.ThrowCheckRequiredString(this.FileName, "fileName")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type LibraryConfig struct {
+	Buttons           []ButtonConfig `json:"buttons" doc:"buttons of the library"`
+	MaxCodesPerButton int            `json:"maxCodesPerButton" doc:"max codes per button, 0 for all"`
+	MinBrands         int            `json:"minBrands" doc:"min brands a code must be seen in"`
+}
+
+func (this LibraryConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckNotZero(len(this.Buttons), "len(buttons)")
+		errs.ThrowCheckNotNegative(this.MaxCodesPerButton, "maxCodesPerButton")
+		errs.ThrowCheckNotNegative(this.MinBrands, "minBrands")
+
+		aliases := map[string]string{}
+		for i, button := range this.Buttons {
+			errs.ThrowCheckValid(button, fmt.Sprintf("buttons[%d]", i))
+			for _, alias := range button.Names() {
+				key := strings.ToLower(alias)
+				if owner, ok := aliases[key]; ok && owner != button.Name {
+					errs.Throw(errs.Errorf("alias '%s' is used by '%s' and '%s'", alias, owner, button.Name))
+				}
+				aliases[key] = button.Name
+			}
+		}
+	})
+}
+
+type ButtonConfig struct {
+	Name    string   `json:"name" doc:"button name"`
+	Aliases []string `json:"aliases" doc:"function names mapped to the button"`
+}
+
+func (this ButtonConfig) Validate() error {
+	return errs.CheckRequiredString(this.Name, "name")
+}
+
+func (this ButtonConfig) Names() []string {
+	return append([]string{this.Name}, this.Aliases...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
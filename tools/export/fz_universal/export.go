@@ -0,0 +1,182 @@
+package export_fz_universal
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/alg"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "EXPORT FZ UNIVERSAL", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	execCfg := cfg
+	execCfg.Target.Folder = execCfg.Target.Folder.Join("result")
+	err = execExportFzUniversal(ctx, execCfg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+func execExportFzUniversal(ctx context.Context, cfg Config) error {
+	l := logs.L(ctx)
+
+	library := newLibraryCollector(cfg.Library)
+	err := signalutils.EnumSignals(ctx, cfg.Source, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(library), nil
+	})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	writer, err := signalutils.NewIrLibraryFileWriter(filepath.Join(cfg.Target.Folder.Path, cfg.Target.FileName))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	count := 0
+	for _, button := range cfg.Library.Buttons {
+		entries := library.entries(button.Name)
+		for _, e := range entries {
+			err = writer.Consume(e.librarySignal(button.Name))
+			if err != nil {
+				return errs.Join(errs.Wrap(err), writer.Close())
+			}
+		}
+		l.I("%-16s: %v", button.Name, len(entries))
+		count += len(entries)
+	}
+
+	l.I("signals: %v of %v", count, library.signalsCount)
+
+	return errs.Wrap(writer.Close())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func newLibraryCollector(cfg LibraryConfig) *libraryCollector {
+	collector := &libraryCollector{
+		cfg:     cfg,
+		aliases: map[string]string{},
+		buttons: map[string]map[string]*libraryEntry{},
+	}
+	for _, button := range cfg.Buttons {
+		for _, alias := range button.Names() {
+			collector.aliases[strings.ToLower(alias)] = button.Name
+		}
+	}
+	return collector
+}
+
+type libraryCollector struct {
+	cfg          LibraryConfig
+	aliases      map[string]string
+	buttons      map[string]map[string]*libraryEntry
+	signalsCount int
+}
+
+type libraryEntry struct {
+	key    string
+	signal signal.Signal
+	brands map[string]struct{}
+}
+
+func (this *libraryCollector) Consume(s signal.Signal) error {
+	this.signalsCount++
+
+	button, ok := this.aliases[strings.ToLower(strings.TrimSpace(s.Function))]
+	if !ok {
+		return nil
+	}
+
+	if s.Protocol == "" && len(s.Data) == 0 {
+		return nil
+	}
+
+	codes, ok := this.buttons[button]
+	if !ok {
+		codes = map[string]*libraryEntry{}
+		this.buttons[button] = codes
+	}
+
+	key := signal.FingerprintOf(s)
+	entry, ok := codes[key]
+	if !ok {
+		entry = &libraryEntry{key: key, signal: s, brands: map[string]struct{}{}}
+		codes[key] = entry
+	}
+	entry.brands[strings.ToLower(s.Brand)] = struct{}{}
+
+	return nil
+}
+
+func (this *libraryCollector) entries(button string) []*libraryEntry {
+	entries := make([]*libraryEntry, 0, len(this.buttons[button]))
+	for _, e := range this.buttons[button] {
+		if len(e.brands) >= this.cfg.MinBrands {
+			entries = append(entries, e)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if len(entries[i].brands) != len(entries[j].brands) {
+			return len(entries[i].brands) > len(entries[j].brands)
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	if this.cfg.MaxCodesPerButton > 0 && len(entries) > this.cfg.MaxCodesPerButton {
+		entries = entries[:this.cfg.MaxCodesPerButton]
+	}
+
+	return entries
+}
+
+func (this *libraryEntry) librarySignal(button string) signal.Signal {
+	const maxListedBrands = 5
+
+	brands := alg.MapKeys(this.brands)
+	sort.Strings(brands)
+	if len(brands) > maxListedBrands {
+		brands = append(brands[:maxListedBrands], "...")
+	}
+
+	s := this.signal
+	s.Function = button
+	s.Meta = signal.Meta{
+		signal.MetaComment: fmt.Sprintf("Brands(%d): %s", len(this.brands), strings.Join(brands, ", ")),
+	}
+	if dutyCycle, ok := this.signal.Meta[signal.MetaDutyCycle]; ok {
+		s.Meta[signal.MetaDutyCycle] = dutyCycle
+	}
+
+	return s
+}
@@ -0,0 +1,83 @@
+package export_template
+
+import (
+	"path/filepath"
+
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Source   string       `json:"source" doc:"folder with parsed signals"`
+	Target   TargetConfig `json:"target" doc:"where to write rendered files"`
+	Template string       `json:"template" doc:"template file"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckRequiredString(this.Source, "source")
+		errs.ThrowCheckRequiredString(this.Template, "template")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Source))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Source, err = filepath.Abs(this.Source)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	if this.Template != "" {
+		this.Template, err = filepath.Abs(this.Template)
+		if err != nil {
+			return this, errs.Wrap(err)
+		}
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder      utils.TargetFolder `json:"folder" doc:"output folder"`
+	ToOneFolder bool               `json:"toOneFolder" doc:"flatten the output into one folder"`
+	Ext         string             `json:"ext" doc:"extension of rendered files"`
+	FileName    string             `json:"fileName" doc:"render everything into this one file"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+		if this.FileName == "" {
+			errs.ThrowCheckRequiredString(this.Ext, "ext")
+		}
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
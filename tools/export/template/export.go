@@ -0,0 +1,78 @@
+package export_template
+
+import (
+	"context"
+	"path/filepath"
+
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "EXPORT TEMPLATE", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source   <-: %s", cfg.Source)
+	l.I("template <-: %s", cfg.Template)
+	l.I("target   ->: %s", cfg.Target.Folder.Path)
+
+	execCfg := cfg
+	execCfg.Target.Folder = execCfg.Target.Folder.Join("result")
+	err = execExportTemplate(ctx, execCfg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return nil
+}
+
+func execExportTemplate(ctx context.Context, cfg Config) (err error) {
+	t, err := signalutils.LoadTemplate(cfg.Template)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	if cfg.Target.FileName != "" {
+		filePath := filepath.Join(cfg.Target.Folder.Path, cfg.Target.FileName)
+		writer, err := signalutils.NewTemplateFileWriter(filePath, filepath.Ext(filePath), t)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		defer func() {
+			err = errs.Join(err, writer.Close())
+		}()
+
+		return signalutils.EnumSignals(ctx, cfg.Source, func(string) (signalutils.ClosableSignalConsumer, error) {
+			return signalutils.NewNopClosingSignalConsumer(writer), nil
+		})
+	}
+
+	getConsumer := func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewTemplateFileWriter(filePath, cfg.Target.Ext, t)
+	}
+
+	getTargetFilePath := signalutils.RepeatSourceTreeTargetFilePathStrategy(cfg.Source, cfg.Target.Folder.Path)
+	if cfg.Target.ToOneFolder {
+		getTargetFilePath = signalutils.ToOneFolderTargetFilePathStrategy(cfg.Source, cfg.Target.Folder.Path)
+	}
+
+	factory := signalutils.NewSignalsToFileConsumersFactory(getConsumer, getTargetFilePath)
+
+	return errs.Wrap(signalutils.EnumSignals(ctx, cfg.Source, factory.NewConsumer))
+}
@@ -3,6 +3,7 @@ package filter
 import (
 	"path/filepath"
 
+	signalutils "irptools/signals/utils"
 	"irptools/tools/utils"
 	"irptools/utils/errs"
 	"irptools/utils/misc"
@@ -10,8 +11,8 @@ import (
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func LoadConfig(filePath string) (Config, error) {
-	return misc.LoadJsonConfigFromFile(filePath, func(cfg Config) (Config, error) {
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
 		return cfg.Adjust()
 	})
 }
@@ -19,9 +20,9 @@ func LoadConfig(filePath string) (Config, error) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type Config struct {
-	Source string         `json:"source"`
-	Target TargetConfig   `json:"target"`
-	Filter map[string]any `json:"filter"`
+	Source string         `json:"source" doc:"folder with parsed signals"`
+	Target TargetConfig   `json:"target" doc:"where to write filtered signals"`
+	Filter map[string]any `json:"filter" doc:"json predicate a signal must satisfy"`
 }
 
 func (this Config) Validate() error {
@@ -52,15 +53,20 @@ func (this Config) Adjust() (Config, error) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type TargetConfig struct {
-	Folder          utils.TargetFolder `json:"folder"`
-	WithStat        bool               `json:"withStat"`
-	PrettyJsonPrint bool               `json:"prettyJsonPrint"`
-	ToOneFolder     bool               `json:"toOneFolder"`
+	Folder          utils.TargetFolder         `json:"folder" doc:"output folder"`
+	WithStat        bool                       `json:"withStat" doc:"write stat.json for the result"`
+	WithStatHtml    bool                       `json:"withStatHtml" doc:"write stat.html for the result"`
+	PrettyJsonPrint bool                       `json:"prettyJsonPrint" doc:"indent json output"`
+	Cleanup         signalutils.CleanupOptions `json:"cleanup" doc:"data cleanup applied to every signal"`
+	ToOneFolder     bool                       `json:"toOneFolder" doc:"flatten the output into one folder"`
+	Layout          string                     `json:"layout" doc:"output layout: tree, jsonl, jsonl.gz or zip"`
 }
 
 func (this TargetConfig) Validate() error {
 	return errs.Catch(func() {
 		errs.ThrowCheckValid(this.Folder, "folder")
+		errs.ThrowCheckValid(this.Cleanup, "cleanup")
+		errs.ThrowIf(signalutils.ValidateLayout(this.Layout))
 	})
 }
 
@@ -2,8 +2,9 @@ package filter
 
 import (
 	"context"
-	"os"
+	"strings"
 
+	"irptools/signals/catalog"
 	"irptools/signals/signal"
 	signalutils "irptools/signals/utils"
 	"irptools/tools/stat"
@@ -42,10 +43,11 @@ func execMain(ctx context.Context, cfg Config) error {
 	}
 
 	if cfg.Target.WithStat {
-		if _, err = os.Stat(execCfg.Target.Folder.Path); !os.IsNotExist(err) {
+		if _, exists := signalutils.ResolveSignalsPath(execCfg.Target.Folder.Path); exists {
 			statCfg := stat.Config{
 				Target: cfg.Target.Folder.Join("stat"),
 				Source: execCfg.Target.Folder.Path,
+				Html:   cfg.Target.WithStatHtml,
 			}
 			err = stat.Main(ctx, statCfg)
 			if err != nil {
@@ -57,27 +59,28 @@ func execMain(ctx context.Context, cfg Config) error {
 	return nil
 }
 
-func execFilter(ctx context.Context, cfg Config) error {
-	jsonPred, err := jsonutils.BuildPredicate(cfg.Filter, jsonutils.DefaultLogic())
+func execFilter(ctx context.Context, cfg Config) (err error) {
+	filter, err := NewSignalFilter(cfg.Filter)
 	if err != nil {
-		return errs.Errorf("failed to build predicate: %w", err)
+		return errs.Wrap(err)
 	}
 
-	var sPtr *signal.Signal
-	sObj := newSignalObject(&sPtr)
-
-	filter := func(s signal.Signal) (bool, error) {
-		sPtr = &s
-		res := jsonPred.Is(&sObj)
-		return res, nil
+	sink, err := signalutils.NewLayoutSink(cfg.Target.Layout, cfg.Target.Folder.Path, cfg.Target.PrettyJsonPrint)
+	if err != nil {
+		return errs.Errorf("failed to create sink: %w", err)
 	}
+	defer func() {
+		err = errs.Join(err, sink.Close())
+	}()
+
+	cleanup := cfg.Target.Cleanup.Transforms()
 
 	getConsumer := func(filePath string) (signalutils.ClosableSignalConsumer, error) {
 		postponing := signalutils.NewPostponingConsumer(func() (signalutils.ClosableSignalConsumer, error) {
-			return signalutils.NewJsonFileWriter(filePath, cfg.Target.PrettyJsonPrint)
+			return sink.NewConsumer(filePath)
 		})
 		filtering := signalutils.NewFilteringSignalConsumer(postponing, filter)
-		return filtering, nil
+		return signalutils.NewTransformingSignalConsumer(filtering, cleanup), nil
 	}
 
 	getTargetFilePath := signalutils.RepeatSourceTreeTargetFilePathStrategy(cfg.Source, cfg.Target.Folder.Path)
@@ -85,6 +88,10 @@ func execFilter(ctx context.Context, cfg Config) error {
 		getTargetFilePath = signalutils.ToOneFolderTargetFilePathStrategy(cfg.Source, cfg.Target.Folder.Path)
 	}
 	factory := signalutils.NewSignalsToFileConsumersFactory(getConsumer, getTargetFilePath)
+	if catalog.Exists(cfg.Source) {
+		return filterCatalog(ctx, cfg, factory.NewConsumer)
+	}
+
 	err = signalutils.EnumSignals(ctx, cfg.Source, factory.NewConsumer)
 	if err != nil {
 		return errs.Wrap(err)
@@ -93,22 +100,84 @@ func execFilter(ctx context.Context, cfg Config) error {
 	return nil
 }
 
-func newSignalObject(sr **signal.Signal) jsonutils.MappedObject {
+func filterCatalog(ctx context.Context, cfg Config, getConsumer signalutils.SignalsToFileConsumerSourceFn) (err error) {
+	c, err := catalog.Open(cfg.Source)
+	if err != nil {
+		return errs.Errorf("failed to open catalog: %w", err)
+	}
+	defer func() {
+		err = errs.Join(err, c.Close())
+	}()
+
+	l := logs.L(ctx)
+	offsets, indexed := jsonutils.LookupIndexed(cfg.Filter, c.Lookup)
+	if indexed {
+		l.I("catalog: %v of %v signals selected by indexes", len(offsets), c.Count())
+	} else {
+		offsets = nil
+		l.I("catalog: filter can't use indexes, scanning %v signals", c.Count())
+	}
+
+	return c.EnumSignals(ctx, offsets, cfg.Source, getConsumer)
+}
+
+func NewSignalFilter(rules map[string]any) (func(signal.Signal) (bool, error), error) {
+	jsonPred, err := jsonutils.BuildPredicate(rules, jsonutils.DefaultLogic())
+	if err != nil {
+		return nil, errs.Errorf("failed to build predicate: %w", err)
+	}
+
+	var sPtr *signal.Signal
+	sObj := newSignalObject(&sPtr)
+
+	return func(s signal.Signal) (bool, error) {
+		sPtr = &s
+		res := jsonPred.Is(&sObj)
+		return res, nil
+	}, nil
+}
+
+type signalObject struct {
+	jsonutils.MappedObject
+	sr **signal.Signal
+}
+
+func (this *signalObject) Field(name string) (any, error) {
+	if key, ok := strings.CutPrefix(name, metaFieldPrefix); ok {
+		value, ok := (*this.sr).Meta[key]
+		if !ok {
+			return nil, jsonutils.ErrUnknownField
+		}
+		return value, nil
+	}
+	return this.MappedObject.Field(name)
+}
+
+const metaFieldPrefix = "meta."
+
+func newSignalObject(sr **signal.Signal) signalObject {
+	return signalObject{sr: sr, MappedObject: newSignalMappedObject(sr)}
+}
+
+func newSignalMappedObject(sr **signal.Signal) jsonutils.MappedObject {
 	return jsonutils.NewMappedObject(map[string]func() (any, error){
-		"id":        func() (any, error) { return (*sr).Id, nil },
-		"source":    func() (any, error) { return (*sr).Source, nil },
-		"brand":     func() (any, error) { return (*sr).Brand, nil },
-		"device":    func() (any, error) { return (*sr).Device, nil },
-		"protocol":  func() (any, error) { return (*sr).Protocol, nil },
-		"function":  func() (any, error) { return (*sr).Function, nil },
-		"frequency": func() (any, error) { return (*sr).Frequency, nil },
-		"data":      func() (any, error) { return (*sr).Data, nil },
-		"Source":    func() (any, error) { return (*sr).Source, nil },
-		"Brand":     func() (any, error) { return (*sr).Brand, nil },
-		"Device":    func() (any, error) { return (*sr).Device, nil },
-		"Protocol":  func() (any, error) { return (*sr).Protocol, nil },
-		"Function":  func() (any, error) { return (*sr).Function, nil },
-		"Frequency": func() (any, error) { return (*sr).Frequency, nil },
-		"Data":      func() (any, error) { return (*sr).Data, nil },
+		"id":          func() (any, error) { return (*sr).Id, nil },
+		"fingerprint": func() (any, error) { return (*sr).Fingerprint, nil },
+		"source":      func() (any, error) { return (*sr).Source, nil },
+		"brand":       func() (any, error) { return (*sr).Brand, nil },
+		"device":      func() (any, error) { return (*sr).Device, nil },
+		"model":       func() (any, error) { return (*sr).Model, nil },
+		"protocol":    func() (any, error) { return (*sr).Protocol, nil },
+		"function":    func() (any, error) { return (*sr).Function, nil },
+		"frequency":   func() (any, error) { return (*sr).Frequency, nil },
+		"data":        func() (any, error) { return (*sr).Data, nil },
+		"Source":      func() (any, error) { return (*sr).Source, nil },
+		"Brand":       func() (any, error) { return (*sr).Brand, nil },
+		"Device":      func() (any, error) { return (*sr).Device, nil },
+		"Model":       func() (any, error) { return (*sr).Model, nil },
+		"Protocol":    func() (any, error) { return (*sr).Protocol, nil },
+		"Function":    func() (any, error) { return (*sr).Function, nil },
+		"Frequency":   func() (any, error) { return (*sr).Frequency, nil },
+		"Data":        func() (any, error) { return (*sr).Data, nil },
 	})
 }
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"context"
+	"io"
+
+	signalutils "irptools/signals/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+func LoadStreamConfig(filePath string, overrides []misc.ConfigOverride) (StreamConfig, error) {
+	return misc.LoadJsonConfigFromFileOrEmpty(filePath, overrides, func(cfg StreamConfig) (StreamConfig, error) {
+		return cfg, nil
+	})
+}
+
+type StreamConfig struct {
+	Target StreamTargetConfig `json:"target" doc:"how filtered signals are written"`
+	Filter map[string]any     `json:"filter" doc:"json predicate a signal must satisfy"`
+}
+
+func (this StreamConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckRequiredObject(this.Filter, "filter")
+	})
+}
+
+type StreamTargetConfig struct {
+	Cleanup signalutils.CleanupOptions `json:"cleanup" doc:"data cleanup applied to every signal"`
+}
+
+func (this StreamTargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Cleanup, "cleanup")
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func Stream(ctx context.Context, cfg StreamConfig, in io.Reader, out io.Writer) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	filter, err := NewSignalFilter(cfg.Filter)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	writer := signalutils.NewJsonWriter(misc.NopWriteCloser(out), false)
+	filtering := signalutils.NewFilteringSignalConsumer(writer, filter)
+	consumer := signalutils.NewTransformingSignalConsumer(filtering, cfg.Target.Cleanup.Transforms())
+
+	err = signalutils.EnumStreamSignals(ctx, in, consumer)
+	return errs.Join(errs.Wrap(err), consumer.Close())
+}
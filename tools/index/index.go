@@ -0,0 +1,58 @@
+package index
+
+import (
+	"context"
+	"path/filepath"
+
+	"irptools/signals/catalog"
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "INDEX", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) (err error) {
+	err = errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	catalogPath := cfg.Target.Folder.Join("result").Path
+	w, err := catalog.OpenWriter(catalogPath)
+	if err != nil {
+		return errs.Errorf("failed to open catalog: %w", err)
+	}
+	defer func() {
+		err = errs.Join(err, w.Close())
+		if err == nil {
+			l.I("signals count = %v", w.Count())
+			l.I("results -> %s", catalogPath)
+		}
+	}()
+
+	return signalutils.EnumSignals(ctx, cfg.Source, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		relPath, err := filepath.Rel(cfg.Source, filePath)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		return signalutils.NewNopClosingSignalConsumer(signalutils.SignalConsumerFn(func(s signal.Signal) error {
+			return w.Append(relPath, s)
+		})), nil
+	})
+}
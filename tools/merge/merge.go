@@ -0,0 +1,122 @@
+package merge
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+
+	"irptools/signals/signal"
+	"irptools/signals/tree"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "MERGE", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("left   <-: %s", cfg.Left)
+	l.I("right  <-: %s", cfg.Right)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	left, err := tree.Load(ctx, cfg.Left)
+	if err != nil {
+		return errs.Errorf("failed to load left tree: %w", err)
+	}
+
+	right, err := tree.Load(ctx, cfg.Right)
+	if err != nil {
+		return errs.Errorf("failed to load right tree: %w", err)
+	}
+
+	c := tree.Compare(left, right, cfg.Match)
+	merged := tree.Merge(left, right, c, cfg.Precedence)
+
+	execCfg := cfg
+	execCfg.Target.Folder = execCfg.Target.Folder.Join("result")
+	err = storeMerged(execCfg, merged)
+	if err != nil {
+		return errs.Errorf("failed to store merged signals: %w", err)
+	}
+
+	r := tree.NewReport(left, right, c, false)
+	err = utils.StoreJson(filepath.Join(cfg.Target.Folder.Path, "merge.json"), r)
+	if err != nil {
+		return errs.Errorf("failed to store report: %w", err)
+	}
+
+	l.I("signals: %v; matched: %v; conflicting: %v; only left: %v; only right: %v",
+		len(merged), r.Matched, r.Conflicting, r.OnlyLeft, r.OnlyRight)
+
+	return nil
+}
+
+func storeMerged(cfg Config, merged []tree.Merged) error {
+	strategies := map[string]signalutils.TargetFilePathStrategyFn{}
+	getTargetFilePath := func(m tree.Merged) (string, error) {
+		strategy, ok := strategies[m.Root]
+		if !ok {
+			strategy = signalutils.RepeatSourceTreeTargetFilePathStrategy(m.Root, cfg.Target.Folder.Path)
+			if cfg.Target.ToOneFolder {
+				strategy = signalutils.ToOneFolderTargetFilePathStrategy(m.Root, cfg.Target.Folder.Path)
+			}
+			strategies[m.Root] = strategy
+		}
+		return strategy(m.FilePath)
+	}
+
+	var filePaths []string
+	files := map[string][]signal.Signal{}
+	for _, m := range merged {
+		filePath, err := getTargetFilePath(m)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		if _, ok := files[filePath]; !ok {
+			filePaths = append(filePaths, filePath)
+		}
+		files[filePath] = append(files[filePath], m.Signal)
+	}
+
+	for _, filePath := range filePaths {
+		err := storeSignals(filePath, files[filePath], cfg.Target.PrettyJsonPrint)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+func storeSignals(filePath string, signals []signal.Signal, prettyJson bool) error {
+	writer, err := signalutils.NewJsonFileWriter(filePath, prettyJson)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	for i, s := range signals {
+		s.Id = strconv.Itoa(i)
+		err = writer.Consume(s)
+		if err != nil {
+			return errs.Join(errs.Wrap(err), writer.Close())
+		}
+	}
+
+	return errs.Wrap(writer.Close())
+}
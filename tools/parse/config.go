@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"irptools/signals/sources/csv"
+	"irptools/signals/sources/fz"
+	"irptools/signals/sources/visio"
+	signalutils "irptools/signals/utils"
 	"irptools/tools/utils"
 	"irptools/utils/errs"
 	"irptools/utils/misc"
@@ -11,8 +15,8 @@ import (
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func LoadConfig(filePath string) (Config, error) {
-	return misc.LoadJsonConfigFromFile(filePath, func(cfg Config) (Config, error) {
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
 		return cfg.Adjust()
 	})
 }
@@ -20,8 +24,8 @@ func LoadConfig(filePath string) (Config, error) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type Config struct {
-	Target  TargetConfig            `json:"target"`
-	Sources map[string]SourceConfig `json:"sources"`
+	Target  TargetConfig            `json:"target" doc:"where to write parsed signals"`
+	Sources map[string]SourceConfig `json:"sources" doc:"sources to parse by name"`
 }
 
 func (this Config) Validate() error {
@@ -57,11 +61,20 @@ func (this Config) Adjust() (Config, error) {
 
 type SourceOptions = interface{}
 
+// options structs per source type, used for config schemas
+func sourceOptionsVariants() map[string]any {
+	return map[string]any{
+		"csv":   csv.Options{},
+		"fz":    fz.Options{},
+		"visio": visio.Options{},
+	}
+}
+
 type SourceConfig struct {
-	Skip    bool          `json:"skip"`
-	Type    string        `json:"type"`
-	Path    string        `json:"path"`
-	Options SourceOptions `json:"options"`
+	Skip    bool          `json:"skip" doc:"do not parse this source"`
+	Type    string        `json:"type" doc:"source type: fz, csv or visio"`
+	Path    string        `json:"path" doc:"source file or folder"`
+	Options SourceOptions `json:"options" doc:"source type specific options"`
 }
 
 func (this SourceConfig) Validate() error {
@@ -71,6 +84,10 @@ func (this SourceConfig) Validate() error {
 	})
 }
 
+func (this SourceConfig) SchemaVariants() (string, string, map[string]any) {
+	return "type", "options", sourceOptionsVariants()
+}
+
 func (this SourceConfig) Adjust() (SourceConfig, error) {
 	var err error
 	this.Path, err = filepath.Abs(this.Path)
@@ -83,16 +100,21 @@ func (this SourceConfig) Adjust() (SourceConfig, error) {
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 type TargetConfig struct {
-	Folder          utils.TargetFolder `json:"folder"`
-	WithStat        bool               `json:"withStat"`
-	PrettyJsonPrint bool               `json:"prettyJsonPrint"`
-	KeepSourceField bool               `json:"keepSourceField"`
-	FieldsToLower   bool               `json:"fieldsToLower"`
+	Folder          utils.TargetFolder         `json:"folder" doc:"output folder"`
+	WithStat        bool                       `json:"withStat" doc:"write stat.json for the result"`
+	WithStatHtml    bool                       `json:"withStatHtml" doc:"write stat.html for the result"`
+	PrettyJsonPrint bool                       `json:"prettyJsonPrint" doc:"indent json output"`
+	Cleanup         signalutils.CleanupOptions `json:"cleanup" doc:"data cleanup applied to every signal"`
+	KeepSourceField bool                       `json:"keepSourceField" doc:"keep the source field in output signals"`
+	FieldsToLower   bool                       `json:"fieldsToLower" doc:"lowercase source, brand, device, model, function and protocol"`
+	Layout          string                     `json:"layout" doc:"output layout: tree, jsonl, jsonl.gz or zip"`
 }
 
 func (this TargetConfig) Validate() error {
 	return errs.Catch(func() {
 		errs.ThrowCheckValid(this.Folder, "folder")
+		errs.ThrowCheckValid(this.Cleanup, "cleanup")
+		errs.ThrowIf(signalutils.ValidateLayout(this.Layout))
 	})
 }
 
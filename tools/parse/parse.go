@@ -3,9 +3,8 @@ package parse
 import (
 	"context"
 	"fmt"
-	"strings"
 
-	"irptools/signals/signal"
+	"irptools/signals/sources/csv"
 	"irptools/signals/sources/fz"
 	"irptools/signals/sources/visio"
 	signalutils "irptools/signals/utils"
@@ -44,6 +43,7 @@ func execMain(ctx context.Context, cfg Config) error {
 		statCfg := stat.Config{
 			Target: cfg.Target.Folder.Join("stat"),
 			Source: execCfg.Target.Folder.Path,
+			Html:   cfg.Target.WithStatHtml,
 		}
 		err = stat.Main(ctx, statCfg)
 		if err != nil {
@@ -54,10 +54,17 @@ func execMain(ctx context.Context, cfg Config) error {
 	return nil
 }
 
-func execParse(ctx context.Context, cfg Config) error {
+func execParse(ctx context.Context, cfg Config) (err error) {
 	l := logs.L(ctx)
 
-	var err error
+	sink, err := signalutils.NewLayoutSink(cfg.Target.Layout, cfg.Target.Folder.Path, cfg.Target.PrettyJsonPrint)
+	if err != nil {
+		return errs.Errorf("failed to create sink: %w", err)
+	}
+	defer func() {
+		err = errs.Join(err, sink.Close())
+	}()
+
 	parsedSignalsCount := 0
 	for k, sourceCfg := range cfg.Sources {
 		scopedCtx, _ := logs.WithScope(ctx, "  ")
@@ -71,7 +78,7 @@ func execParse(ctx context.Context, cfg Config) error {
 
 			targetCfg := cfg.Target
 			targetCfg.Folder = targetCfg.Folder.Join(k)
-			count, err := parseSource(ctx, sourceCfg, targetCfg)
+			count, err := parseSource(ctx, sourceCfg, targetCfg, sink.NewConsumer)
 			l.I("parsed: %v -> %s", count, targetCfg.Folder.Path)
 			parsedSignalsCount += count
 			if err != nil {
@@ -93,24 +100,26 @@ func execParse(ctx context.Context, cfg Config) error {
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-func parseSource(ctx context.Context, sourceCfg SourceConfig, targetCfg TargetConfig) (int, error) {
+func parseSource(ctx context.Context, sourceCfg SourceConfig, targetCfg TargetConfig, getWriter signalutils.SignalsToFileConsumerSourceFn) (int, error) {
 	if sourceCfg.Skip {
 		return 0, nil
 	}
 
+	consumers, err := newSignalConsumersFactory(sourceCfg, targetCfg, getWriter)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+
+	return ParseSource(ctx, sourceCfg, consumers.NewConsumer)
+}
+
+func ParseSource(ctx context.Context, sourceCfg SourceConfig, getConsumer signalutils.SignalsToFileConsumerSourceFn) (int, error) {
 	parse, ok := getAdaptedParsers()[sourceCfg.Type]
 	if !ok {
 		return 0, errs.Errorf("unknown source type: '%s'", sourceCfg.Type)
 	}
 
-	consumers, err := newSignalConsumersFactory(sourceCfg, targetCfg, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
-		return signalutils.NewJsonFileWriter(filePath, targetCfg.PrettyJsonPrint)
-	})
-	if err != nil {
-		return 0, errs.Wrap(err)
-	}
-
-	count, err := parse(ctx, sourceCfg.Path, sourceCfg.Options, consumers)
+	count, err := parse(ctx, sourceCfg.Path, sourceCfg.Options, getConsumer)
 	if err != nil {
 		return count, errs.Errorf("failed to parse '%s' source: %w", sourceCfg.Type, err)
 	}
@@ -118,28 +127,37 @@ func parseSource(ctx context.Context, sourceCfg SourceConfig, targetCfg TargetCo
 	return count, nil
 }
 
-type AdaptedParseFn = func(ctx context.Context, path string, opts SourceOptions, consumerFactory *signalutils.SignalsToFileConsumersFactory) (int, error)
+type AdaptedParseFn = func(ctx context.Context, path string, opts SourceOptions, getConsumer signalutils.SignalsToFileConsumerSourceFn) (int, error)
 
 func getAdaptedParsers() map[string]AdaptedParseFn {
 	parsers := map[string]AdaptedParseFn{
+		"csv":   adaptCsvParser(),
 		"fz":    adaptFzParser(),
 		"visio": adaptVisioParser(),
 	}
 	return parsers
 }
 
+func adaptCsvParser() AdaptedParseFn {
+	return func(ctx context.Context, path string, opts SourceOptions, getConsumer signalutils.SignalsToFileConsumerSourceFn) (int, error) {
+		return csv.ParseCsvFiles(ctx, path, opts, func(filePath string) (csv.ClosableSignalConsumer, error) {
+			return getConsumer(filePath)
+		})
+	}
+}
+
 func adaptFzParser() AdaptedParseFn {
-	return func(ctx context.Context, path string, opts SourceOptions, consumerFactory *signalutils.SignalsToFileConsumersFactory) (int, error) {
+	return func(ctx context.Context, path string, opts SourceOptions, getConsumer signalutils.SignalsToFileConsumerSourceFn) (int, error) {
 		return fz.ParseIrFiles(ctx, path, opts, func(filePath string) (fz.ClosableSignalConsumer, error) {
-			return consumerFactory.NewConsumer(filePath)
+			return getConsumer(filePath)
 		})
 	}
 }
 
 func adaptVisioParser() AdaptedParseFn {
-	return func(ctx context.Context, path string, opts SourceOptions, consumerFactory *signalutils.SignalsToFileConsumersFactory) (int, error) {
+	return func(ctx context.Context, path string, opts SourceOptions, getConsumer signalutils.SignalsToFileConsumerSourceFn) (int, error) {
 		return visio.ParseCsvFiles(ctx, path, opts, func(filePath string) (visio.ClosableSignalConsumer, error) {
-			return consumerFactory.NewConsumer(filePath)
+			return getConsumer(filePath)
 		})
 	}
 }
@@ -149,26 +167,13 @@ func newSignalConsumersFactory(
 	targetCfg TargetConfig,
 	getConsumer signalutils.SignalsToFileConsumerSourceFn) (*signalutils.SignalsToFileConsumersFactory, error) {
 
-	var trs []signalutils.TransformSignalFn
+	trs := targetCfg.Cleanup.Transforms()
 	if !targetCfg.KeepSourceField {
-		trs = append(trs, func(signal signal.Signal) (signal.Signal, error) {
-			signal.Source = ""
-			return signal, nil
-		})
+		trs = append(trs, signalutils.DropSourceField)
 	}
 
 	if targetCfg.FieldsToLower {
-		toLower := func(str *string) {
-			*str = strings.ToLower(*str)
-		}
-		trs = append(trs, func(signal signal.Signal) (signal.Signal, error) {
-			toLower(&signal.Source)
-			toLower(&signal.Brand)
-			toLower(&signal.Device)
-			toLower(&signal.Function)
-			toLower(&signal.Protocol)
-			return signal, nil
-		})
+		trs = append(trs, signalutils.FieldsToLower)
 	}
 
 	if len(trs) != 0 {
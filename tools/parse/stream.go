@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"context"
+	"io"
+
+	"irptools/signals/sources/csv"
+	"irptools/signals/sources/fz"
+	"irptools/signals/sources/visio"
+	signalutils "irptools/signals/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+	"irptools/utils/misc"
+)
+
+func LoadStreamConfig(filePath string, overrides []misc.ConfigOverride) (StreamConfig, error) {
+	return misc.LoadJsonConfigFromFileOrEmpty(filePath, overrides, func(cfg StreamConfig) (StreamConfig, error) {
+		return cfg.Adjust(), nil
+	})
+}
+
+type StreamConfig struct {
+	Type    string             `json:"type" doc:"source type: fz, csv or visio"`
+	Options SourceOptions      `json:"options" doc:"source type specific options"`
+	Target  StreamTargetConfig `json:"target" doc:"how parsed signals are written"`
+}
+
+func (this StreamConfig) Validate() error {
+	return errs.Catch(func() {
+		if _, ok := getStreamParsers()[this.Type]; !ok && this.Type != "" {
+			errs.Throw(errs.Errorf("unknown source type: '%s'", this.Type))
+		}
+		errs.ThrowCheckValid(this.Target, "target")
+	})
+}
+
+func (this StreamConfig) Adjust() StreamConfig {
+	if this.Type == "" {
+		this.Type = "fz"
+	}
+	return this
+}
+
+type StreamTargetConfig struct {
+	Cleanup         signalutils.CleanupOptions `json:"cleanup" doc:"data cleanup applied to every signal"`
+	KeepSourceField bool                       `json:"keepSourceField" doc:"keep the source field in output signals"`
+	FieldsToLower   bool                       `json:"fieldsToLower" doc:"lowercase source, brand, device, model, function and protocol"`
+}
+
+func (this StreamTargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Cleanup, "cleanup")
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func Stream(ctx context.Context, cfg StreamConfig, in io.Reader, out io.Writer) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	trs := cfg.Target.Cleanup.Transforms()
+	if !cfg.Target.KeepSourceField {
+		trs = append(trs, signalutils.DropSourceField)
+	}
+	if cfg.Target.FieldsToLower {
+		trs = append(trs, signalutils.FieldsToLower)
+	}
+
+	writer := signalutils.NewJsonWriter(misc.NopWriteCloser(out), false)
+	consumer := signalutils.NewTransformingSignalConsumer(writer, trs)
+
+	count, err := getStreamParsers()[cfg.Type](in, cfg.Options, consumer)
+	logs.L(ctx).I("parsed: %v", count)
+	if err != nil {
+		return errs.Errorf("failed to parse '%s' stream: %w", cfg.Type, err)
+	}
+
+	return errs.Wrap(consumer.Close())
+}
+
+type StreamParseFn = func(stream io.Reader, opts SourceOptions, consumer signalutils.SignalConsumer) (int, error)
+
+func getStreamParsers() map[string]StreamParseFn {
+	return map[string]StreamParseFn{
+		"csv": func(stream io.Reader, opts SourceOptions, consumer signalutils.SignalConsumer) (int, error) {
+			return csv.ParseCsvReader(stream, opts, consumer.Consume)
+		},
+		"fz": func(stream io.Reader, opts SourceOptions, consumer signalutils.SignalConsumer) (int, error) {
+			return fz.ParseIrReader(stream, opts, consumer)
+		},
+		"visio": func(stream io.Reader, opts SourceOptions, consumer signalutils.SignalConsumer) (int, error) {
+			return visio.ParseCsvReader(stream, consumer)
+		},
+	}
+}
@@ -0,0 +1,219 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+
+	signalutils "irptools/signals/utils"
+	export_csv "irptools/tools/export/csv"
+	"irptools/tools/parse"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Target  TargetConfig                  `json:"target" doc:"where to write stage outputs"`
+	Sources map[string]parse.SourceConfig `json:"sources" doc:"sources to parse by name"`
+	Stages  []StageConfig                 `json:"stages" doc:"stages applied in order"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckNotZero(len(this.Sources), "len(sources)")
+		for key, src := range this.Sources {
+			errs.ThrowCheckValid(src, fmt.Sprintf("source[%s]", key))
+			errs.ThrowIf(this.Target.Folder.ValidateSourcePath(src.Path))
+		}
+		errs.ThrowCheckNotZero(len(this.Stages), "len(stages)")
+		names := map[string]struct{}{}
+		for i, stage := range this.Stages {
+			errs.ThrowCheckValid(stage, fmt.Sprintf("stages[%d]", i))
+			if _, ok := names[stage.Name]; ok {
+				errs.Throw(errs.Errorf("stages[%d]: duplicated name: '%s'", i, stage.Name))
+			}
+			names[stage.Name] = struct{}{}
+		}
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	for k, src := range this.Sources {
+		this.Sources[k], err = src.Adjust()
+		if err != nil {
+			return this, errs.Wrap(err)
+		}
+	}
+
+	for i, stage := range this.Stages {
+		this.Stages[i] = stage.Adjust(i)
+	}
+
+	return this, nil
+}
+
+func (this Config) SourceKeys() []string {
+	keys := make([]string, 0, len(this.Sources))
+	for k := range this.Sources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder utils.TargetFolder `json:"folder" doc:"output folder"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	StageTypeTransform = "transform"
+	StageTypeFilter    = "filter"
+	StageTypeCompact   = "compact"
+	StageTypeStat      = "stat"
+	StageTypeSink      = "sink"
+)
+
+type StageConfig struct {
+	Skip      bool                       `json:"skip" doc:"do not run this stage"`
+	Type      string                     `json:"type" doc:"stage type: transform, filter, compact, stat or sink"`
+	Name      string                     `json:"name" doc:"stage output folder name"`
+	Transform TransformOptions           `json:"transform" doc:"options of a transform stage"`
+	Filter    map[string]any             `json:"filter" doc:"json predicate of a filter stage"`
+	Compact   signalutils.CompactOptions `json:"compact" doc:"options of a compact stage"`
+	Stat      StatOptions                `json:"stat" doc:"options of a stat stage"`
+	Sink      SinkOptions                `json:"sink" doc:"options of a sink stage"`
+}
+
+func (this StageConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckRequiredString(this.Name, "name")
+		switch this.Type {
+		case StageTypeTransform:
+			errs.ThrowCheckValid(this.Transform, "transform")
+		case StageTypeFilter:
+			errs.ThrowCheckRequiredObject(this.Filter, "filter")
+		case StageTypeCompact:
+			errs.ThrowCheckValid(this.Compact, "compact")
+		case StageTypeStat:
+		case StageTypeSink:
+			errs.ThrowCheckValid(this.Sink, "sink")
+		default:
+			errs.Throw(errs.Errorf("unexpected type: '%s'", this.Type))
+		}
+	})
+}
+
+func (this StageConfig) Adjust(idx int) StageConfig {
+	if this.Name == "" {
+		this.Name = fmt.Sprintf("%02d_%s", idx, this.Type)
+	}
+	this.Compact = this.Compact.Adjust()
+	this.Sink = this.Sink.Adjust()
+	return this
+}
+
+type TransformOptions struct {
+	Cleanup         signalutils.CleanupOptions `json:"cleanup" doc:"data cleanup applied to every signal"`
+	DropSourceField bool                       `json:"dropSourceField" doc:"drop the source field"`
+	FieldsToLower   bool                       `json:"fieldsToLower" doc:"lowercase source, brand, device, model, function and protocol"`
+}
+
+func (this TransformOptions) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Cleanup, "cleanup")
+	})
+}
+
+func (this TransformOptions) Transforms() []signalutils.TransformSignalFn {
+	trs := this.Cleanup.Transforms()
+	if this.DropSourceField {
+		trs = append(trs, signalutils.DropSourceField)
+	}
+	if this.FieldsToLower {
+		trs = append(trs, signalutils.FieldsToLower)
+	}
+	return trs
+}
+
+type StatOptions struct {
+	Html bool `json:"html" doc:"also write stat.html"`
+}
+
+const (
+	SinkFormatJson = "json"
+	SinkFormatIr   = "ir"
+	SinkFormatCsv  = "csv"
+)
+
+type SinkOptions struct {
+	Format          string                  `json:"format" doc:"output format: json, ir or csv"`
+	PrettyJsonPrint bool                    `json:"prettyJsonPrint" doc:"indent json output"`
+	ToOneFolder     bool                    `json:"toOneFolder" doc:"flatten the output into one folder"`
+	FileName        string                  `json:"fileName" doc:"csv file name"`
+	SplitBy         string                  `json:"splitBy" doc:"split csv files by: none, brand or device"`
+	Csv             export_csv.FormatConfig `json:"csv" doc:"csv format options"`
+}
+
+func (this SinkOptions) Validate() error {
+	return errs.Catch(func() {
+		switch this.Format {
+		case SinkFormatJson, SinkFormatIr:
+		case SinkFormatCsv:
+			errs.ThrowCheckValid(this.Csv, "csv")
+			switch this.SplitBy {
+			case export_csv.SplitByNone, export_csv.SplitByBrand, export_csv.SplitByDevice:
+			default:
+				errs.Throw(errs.Errorf("unexpected splitBy: '%s'", this.SplitBy))
+			}
+		default:
+			errs.Throw(errs.Errorf("unexpected format: '%s'", this.Format))
+		}
+	})
+}
+
+func (this SinkOptions) Adjust() SinkOptions {
+	if this.Format == "" {
+		this.Format = SinkFormatJson
+	}
+	if this.FileName == "" {
+		this.FileName = "signals"
+	}
+	this.Csv = this.Csv.Adjust()
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,244 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	export_csv "irptools/tools/export/csv"
+	"irptools/tools/filter"
+	"irptools/tools/parse"
+	"irptools/tools/stat"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "PIPELINE", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	for _, k := range cfg.SourceKeys() {
+		l.I("source <-: %s.%s: %s", k, cfg.Sources[k].Type, cfg.Sources[k].Path)
+	}
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	root := cfg.Target.Folder.Path
+	stages := make([]*stage, 0, len(cfg.Stages))
+	for _, stageCfg := range cfg.Stages {
+		if stageCfg.Skip {
+			l.I("stage %s: skipped", stageCfg.Name)
+			continue
+		}
+		s, err := newStage(root, stageCfg)
+		if err != nil {
+			return errs.Errorf("failed to create stage '%s': %w", stageCfg.Name, err)
+		}
+		stages = append(stages, s)
+	}
+
+	getConsumer := func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(signalutils.SignalConsumerFn(func(signal.Signal) error {
+			return nil
+		})), nil
+	}
+	for i := len(stages) - 1; i >= 0; i-- {
+		getConsumer = stages[i].chain(getConsumer)
+	}
+
+	parsedSignalsCount := 0
+	for _, k := range cfg.SourceKeys() {
+		sourceCfg := cfg.Sources[k]
+		if sourceCfg.Skip {
+			l.I("source %s: skipped", k)
+			continue
+		}
+
+		getKey := signalutils.RepeatSourceTreeTargetFilePathStrategy(sourceCfg.Path, filepath.Join(root, k))
+		count, err := parse.ParseSource(ctx, sourceCfg, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+			key, err := getKey(filePath)
+			if err != nil {
+				return nil, errs.Wrap(err)
+			}
+			return getConsumer(key)
+		})
+		parsedSignalsCount += count
+		if err != nil {
+			return errs.Errorf("failed to process source '%s': %w", k, err)
+		}
+	}
+
+	l.I("signals count = %v", parsedSignalsCount)
+	for _, s := range stages {
+		l.I("stage %s: %v", s.name, s.count)
+		if s.finish != nil {
+			err = s.finish(ctx)
+			if err != nil {
+				return errs.Errorf("failed to finish stage '%s': %w", s.name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type stage struct {
+	name   string
+	count  int
+	wrap   func(next signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn
+	finish func(ctx context.Context) error
+}
+
+func (this *stage) chain(next signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn {
+	getConsumer := this.wrap(next)
+	count := func(s signal.Signal) (signal.Signal, error) {
+		this.count++
+		return s, nil
+	}
+	return func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		consumer, err := getConsumer(filePath)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		return signalutils.NewTransformingSignalConsumer(consumer, []signalutils.TransformSignalFn{count}), nil
+	}
+}
+
+func newStage(root string, cfg StageConfig) (*stage, error) {
+	s := &stage{name: cfg.Name}
+
+	switch cfg.Type {
+	case StageTypeTransform:
+		s.wrap = transformStage(cfg.Transform.Transforms())
+
+	case StageTypeFilter:
+		pred, err := filter.NewSignalFilter(cfg.Filter)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		s.wrap = func(next signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn {
+			return func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+				consumer, err := next(filePath)
+				if err != nil {
+					return nil, errs.Wrap(err)
+				}
+				return signalutils.NewFilteringSignalConsumer(consumer, pred), nil
+			}
+		}
+
+	case StageTypeCompact:
+		converted := 0
+		s.wrap = transformStage([]signalutils.TransformSignalFn{func(sig signal.Signal) (signal.Signal, error) {
+			sig, result := cfg.Compact.Compact(sig)
+			if result.Converted {
+				converted++
+			}
+			return sig, nil
+		}})
+		s.finish = func(ctx context.Context) error {
+			logs.L(ctx).I("stage %s: converted: %v", cfg.Name, converted)
+			return nil
+		}
+
+	case StageTypeStat:
+		collector := stat.NewCollector(root)
+		s.wrap = teeStage(collector.NewConsumer)
+		s.finish = func(ctx context.Context) error {
+			folder, err := utils.TargetFolder{Path: filepath.Join(root, cfg.Name), WithoutCreationTime: true}.PrepareTarget()
+			if err != nil {
+				return errs.Wrap(err)
+			}
+			return stat.Store(ctx, folder, fmt.Sprintf("Signals statistics: %s", cfg.Name), collector.Stat(), cfg.Stat.Html)
+		}
+
+	case StageTypeSink:
+		getSink, closeSink := newSink(filepath.Join(root, cfg.Name), root, cfg.Sink)
+		s.wrap = teeStage(getSink)
+		s.finish = func(ctx context.Context) error {
+			logs.L(ctx).I("stage %s: results -> %s", cfg.Name, filepath.Join(root, cfg.Name))
+			return errs.Wrap(closeSink())
+		}
+
+	default:
+		return nil, errs.Errorf("unexpected stage type: '%s'", cfg.Type)
+	}
+
+	return s, nil
+}
+
+func transformStage(trs []signalutils.TransformSignalFn) func(signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn {
+	return func(next signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn {
+		return func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+			consumer, err := next(filePath)
+			if err != nil {
+				return nil, errs.Wrap(err)
+			}
+			return signalutils.NewTransformingSignalConsumer(consumer, trs), nil
+		}
+	}
+}
+
+func teeStage(getTee signalutils.SignalsToFileConsumerSourceFn) func(signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn {
+	return func(next signalutils.SignalsToFileConsumerSourceFn) signalutils.SignalsToFileConsumerSourceFn {
+		return func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+			tee, err := getTee(filePath)
+			if err != nil {
+				return nil, errs.Wrap(err)
+			}
+			consumer, err := next(filePath)
+			if err != nil {
+				return nil, errs.Join(errs.Wrap(err), tee.Close())
+			}
+			return signalutils.NewTeeSignalConsumer(tee, consumer), nil
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func newSink(dirPath string, root string, opts SinkOptions) (signalutils.SignalsToFileConsumerSourceFn, func() error) {
+	if opts.Format == SinkFormatCsv {
+		sink := export_csv.NewSink(dirPath, opts.FileName, opts.SplitBy, opts.Csv)
+		return func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+			return signalutils.NewNopClosingSignalConsumer(sink), nil
+		}, sink.Close
+	}
+
+	getWriter := func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		if opts.Format == SinkFormatIr {
+			return signalutils.NewIrFileWriter(filePath)
+		}
+		return signalutils.NewJsonFileWriter(filePath, opts.PrettyJsonPrint)
+	}
+
+	getTargetFilePath := signalutils.RepeatSourceTreeTargetFilePathStrategy(root, dirPath)
+	if opts.ToOneFolder {
+		getTargetFilePath = signalutils.ToOneFolderTargetFilePathStrategy(root, dirPath)
+	}
+
+	factory := signalutils.NewSignalsToFileConsumersFactory(func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewPostponingConsumer(func() (signalutils.ClosableSignalConsumer, error) {
+			return getWriter(filePath)
+		}), nil
+	}, getTargetFilePath)
+
+	return factory.NewConsumer, func() error { return nil }
+}
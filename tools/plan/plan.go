@@ -0,0 +1,98 @@
+package plan
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"irptools/signals/irp"
+	signalplan "irptools/signals/plan"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "PLAN", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	p, err := execPlan(ctx, cfg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = storeSignals(filepath.Join(cfg.Target.Folder.Path, "result", "signals.json"), p, cfg.Target.PrettyJsonPrint)
+	if err != nil {
+		return errs.Errorf("failed to store signals: %w", err)
+	}
+
+	err = storeSummary(filepath.Join(cfg.Target.Folder.Path, "plan.json"), p)
+	if err != nil {
+		return errs.Errorf("failed to store summary: %w", err)
+	}
+
+	l.I("signals: %v; groups: %v; total time: %v", p.Signals, len(p.Groups), microsToDuration(p.TotalTime))
+
+	return nil
+}
+
+func execPlan(ctx context.Context, cfg Config) (signalplan.Plan, error) {
+	planner := signalplan.NewPlanner(cfg.Plan)
+	err := signalutils.EnumSignals(ctx, cfg.Source, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(planner), nil
+	})
+	if err != nil {
+		return signalplan.Plan{}, errs.Wrap(err)
+	}
+	return planner.Plan(), nil
+}
+
+func storeSignals(filePath string, p signalplan.Plan, prettyJson bool) error {
+	writer, err := signalutils.NewJsonFileWriter(filePath, prettyJson)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	for _, item := range p.Items {
+		err = writer.Consume(item.Signal)
+		if err != nil {
+			return errs.Join(errs.Wrap(err), writer.Close())
+		}
+	}
+
+	return errs.Wrap(writer.Close())
+}
+
+func storeSummary(filePath string, p signalplan.Plan) error {
+	summary := struct {
+		signalplan.Plan
+		TotalDuration string `json:"totalDuration"`
+	}{
+		Plan:          p,
+		TotalDuration: microsToDuration(p.TotalTime).String(),
+	}
+
+	return utils.StoreJson(filePath, summary)
+}
+
+func microsToDuration(micros irp.Micros) time.Duration {
+	return time.Duration(micros) * time.Microsecond
+}
@@ -0,0 +1,429 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"irptools/signals/signal"
+	"irptools/signals/tree"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/filter"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+	"irptools/utils/misc"
+)
+
+const (
+	FormatJson   = "json"
+	FormatIr     = "ir"
+	FormatPronto = "pronto"
+)
+
+type api struct {
+	ctx         context.Context
+	tree        tree.Tree
+	maxPageSize int
+}
+
+func newApi(ctx context.Context, t tree.Tree, maxPageSize int) *api {
+	return &api{ctx: ctx, tree: t, maxPageSize: maxPageSize}
+}
+
+func (this *api) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/brands", this.handleValues(func(s signal.Signal) string { return s.Brand }))
+	mux.HandleFunc("GET /api/devices", this.handleValues(func(s signal.Signal) string { return s.Device }))
+	mux.HandleFunc("GET /api/functions", this.handleValues(func(s signal.Signal) string { return s.Function }))
+	mux.HandleFunc("GET /api/signals", this.handleSignals)
+	mux.HandleFunc("POST /api/signals", this.handleSignals)
+	mux.HandleFunc("GET /api/signals/{id}", this.handleSignal)
+	mux.HandleFunc("GET /api/signals/{id}/export", this.handleSignalExport)
+	mux.HandleFunc("GET /api/export", this.handleExport)
+	mux.HandleFunc("POST /api/export", this.handleExport)
+	mux.HandleFunc("POST /api/remote", this.handleRemote)
+	mux.Handle("GET /", uiHandler())
+
+	l := logs.L(this.ctx)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l.I("%s %s", r.Method, r.URL)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type valueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+func (this *api) handleValues(get func(s signal.Signal) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entries, err := this.query(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		counts := map[string]int{}
+		for _, e := range entries {
+			counts[get(e.Signal)]++
+		}
+
+		result := make([]valueCount, 0, len(counts))
+		for value, count := range counts {
+			result = append(result, valueCount{Value: value, Count: count})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].Value < result[j].Value
+		})
+
+		writeJson(w, result)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type signalItem struct {
+	Id     int           `json:"id"`
+	File   string        `json:"file"`
+	Signal signal.Signal `json:"signal"`
+}
+
+type signalsPage struct {
+	Total  int          `json:"total"`
+	Offset int          `json:"offset"`
+	Items  []signalItem `json:"items"`
+}
+
+func (this *api) handleSignals(w http.ResponseWriter, r *http.Request) {
+	entries, err := this.query(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	offset, err := intParam(r, "offset", 0)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, err := intParam(r, "limit", this.maxPageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	limit = min(limit, this.maxPageSize)
+	offset = min(offset, len(entries))
+
+	page := signalsPage{Total: len(entries), Offset: offset, Items: []signalItem{}}
+	for _, e := range entries[offset:min(offset+limit, len(entries))] {
+		page.Items = append(page.Items, this.item(e))
+	}
+
+	writeJson(w, page)
+}
+
+type signalDetails struct {
+	signalItem
+	Expanded bool   `json:"expanded"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (this *api) handleSignal(w http.ResponseWriter, r *http.Request) {
+	e, ok := this.entry(w, r)
+	if !ok {
+		return
+	}
+
+	d := signalDetails{signalItem: this.item(e)}
+	expanded, err := signalutils.ExpandData(e.Signal)
+	if err != nil {
+		d.Error = err.Error()
+	} else {
+		d.Expanded = len(e.Signal.Data) == 0 && len(expanded.Data) != 0
+		d.Signal = expanded
+	}
+
+	writeJson(w, d)
+}
+
+func (this *api) handleSignalExport(w http.ResponseWriter, r *http.Request) {
+	e, ok := this.entry(w, r)
+	if !ok {
+		return
+	}
+
+	this.export(w, r, []tree.Entry{e}, strconv.Itoa(e.Index), true)
+}
+
+func (this *api) handleExport(w http.ResponseWriter, r *http.Request) {
+	entries, err := this.query(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	this.export(w, r, entries, "signals", false)
+}
+
+type remoteButton struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type remoteRequest struct {
+	Name    string         `json:"name"`
+	Buttons []remoteButton `json:"buttons"`
+}
+
+func (this *api) handleRemote(w http.ResponseWriter, r *http.Request) {
+	req := remoteRequest{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errs.Errorf("bad remote: %w", err))
+		return
+	}
+	if len(req.Buttons) == 0 {
+		writeError(w, http.StatusBadRequest, errs.Error("bad remote: no buttons"))
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	writer, err := signalutils.NewIrWriter(misc.NopWriteCloser(buf), signalutils.IrFileTypeSignals)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for _, b := range req.Buttons {
+		if b.Id < 0 || b.Id >= len(this.tree.Entries) {
+			writeError(w, http.StatusNotFound, errs.Errorf("signal not found: '%v'", b.Id))
+			return
+		}
+
+		s := this.tree.Entries[b.Id].Signal
+		if b.Name != "" {
+			s.Function = b.Name
+		}
+
+		err = writer.Consume(s)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	name := req.Name
+	if name == "" {
+		name = "remote"
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".ir"))
+	_, _ = w.Write(buf.Bytes())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *api) export(w http.ResponseWriter, r *http.Request, entries []tree.Entry, name string, strict bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatJson
+	}
+
+	var (
+		consumer    signalutils.ClosableSignalConsumer
+		contentType string
+		ext         string
+		err         error
+	)
+
+	buf := &bytes.Buffer{}
+	switch format {
+	case FormatJson:
+		consumer, contentType, ext = signalutils.NewJsonWriter(misc.NopWriteCloser(buf), false), "application/x-ndjson", ".json"
+	case FormatIr:
+		consumer, err = signalutils.NewIrWriter(misc.NopWriteCloser(buf), signalutils.IrFileTypeSignals)
+		contentType, ext = "text/plain; charset=utf-8", ".ir"
+	case FormatPronto:
+		consumer, contentType, ext = signalutils.NewProntoWriter(misc.NopWriteCloser(buf)), "text/plain; charset=utf-8", ".txt"
+	default:
+		err = errs.Errorf("unexpected format: '%s', expected %s, %s or %s", format, FormatJson, FormatIr, FormatPronto)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	skipped := 0
+	for _, e := range entries {
+		s := e.Signal
+		if format == FormatPronto {
+			s, err = signalutils.ExpandData(s)
+			if err == nil && len(s.Data) == 0 {
+				err = errs.Errorf("signal without data: '%s'", s.Function)
+			}
+			if err != nil {
+				if strict {
+					writeError(w, http.StatusUnprocessableEntity, err)
+					return
+				}
+				skipped++
+				continue
+			}
+		}
+
+		err = consumer.Consume(s)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	err = consumer.Close()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+ext))
+	w.Header().Set("X-Skipped-Signals", strconv.Itoa(skipped))
+	_, _ = w.Write(buf.Bytes())
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this *api) query(r *http.Request) ([]tree.Entry, error) {
+	rules, err := queryRules(r)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if len(rules) == 0 {
+		return this.tree.Entries, nil
+	}
+
+	pred, err := filter.NewSignalFilter(rules)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	result := []tree.Entry{}
+	for _, e := range this.tree.Entries {
+		ok, err := pred(e.Signal)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		if ok {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func queryRules(r *http.Request) (map[string]any, error) {
+	conditions := []any{}
+
+	q := r.URL.Query()
+	for _, field := range []string{"brand", "device", "function", "protocol"} {
+		if q.Has(field) {
+			conditions = append(conditions, map[string]any{field: q.Get(field)})
+		}
+	}
+
+	var data []byte
+	if q.Has("filter") {
+		data = []byte(q.Get("filter"))
+	} else if r.Method == http.MethodPost {
+		var err error
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+
+	if len(bytes.TrimSpace(data)) != 0 {
+		rules := map[string]any{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err := decoder.Decode(&rules)
+		if err != nil {
+			return nil, errs.Errorf("bad filter: %w", err)
+		}
+		if len(rules) != 0 {
+			conditions = append(conditions, rules)
+		}
+	}
+
+	switch len(conditions) {
+	case 0:
+		return map[string]any{}, nil
+	case 1:
+		return conditions[0].(map[string]any), nil
+	}
+
+	and := make([]any, 0, len(conditions))
+	for _, c := range conditions {
+		for k, v := range c.(map[string]any) {
+			and = append(and, map[string]any{k: v})
+		}
+	}
+	return map[string]any{"$and": and}, nil
+}
+
+func (this *api) entry(w http.ResponseWriter, r *http.Request) (tree.Entry, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 0 || id >= len(this.tree.Entries) {
+		writeError(w, http.StatusNotFound, errs.Errorf("signal not found: '%s'", r.PathValue("id")))
+		return tree.Entry{}, false
+	}
+	return this.tree.Entries[id], true
+}
+
+func (this *api) item(e tree.Entry) signalItem {
+	file, err := filepath.Rel(this.tree.Root, e.FilePath)
+	if err != nil {
+		file = e.FilePath
+	}
+	return signalItem{Id: e.Index, File: filepath.ToSlash(file), Signal: e.Signal}
+}
+
+func intParam(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errs.Errorf("bad %s: '%s'", name, value)
+	}
+	return n, nil
+}
+
+func writeJson(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,205 @@
+package serve
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/signals/tree"
+)
+
+func testApi() http.Handler {
+	root := filepath.Join("out", "signals")
+	entries := []tree.Entry{
+		{Signal: signal.Signal{Brand: "LG", Device: "TV", Function: "Power", Protocol: "NEC", Code: irp.SignalCode{Address: [4]uint8{0x04}, Command: [4]uint8{0x08}}}},
+		{Signal: signal.Signal{Brand: "LG", Device: "TV", Function: "Mute", Frequency: 38000, Data: irp.SignalData{9000, 4500, 560, 40000}}},
+		{Signal: signal.Signal{Brand: "Sony", Device: "TV", Function: "Power", Protocol: "Unknown"}},
+		{Signal: signal.Signal{Brand: "Samsung", Device: "AC", Function: "Power", Frequency: 38000, Data: irp.SignalData{4500, 4500, 560, 40000}}},
+	}
+	for i := range entries {
+		entries[i].Index = i
+		entries[i].FilePath = filepath.Join(root, entries[i].Signal.Brand+".ir.json")
+	}
+	return newApi(context.Background(), tree.Tree{Root: root, Entries: entries}, 3).Handler()
+}
+
+func testRequest(handler http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func pageIds(t *testing.T, w *httptest.ResponseRecorder) (signalsPage, []int) {
+	page := signalsPage{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	ids := []int{}
+	for _, item := range page.Items {
+		ids = append(ids, item.Id)
+	}
+	return page, ids
+}
+
+func Test_Api_SignalsPaging(t *testing.T) {
+	handler := testApi()
+
+	tests := []struct {
+		name   string
+		target string
+		status int
+		offset int
+		ids    []int
+	}{
+		{"first page", "/api/signals", http.StatusOK, 0, []int{0, 1, 2}},
+		{"limit", "/api/signals?limit=1", http.StatusOK, 0, []int{0}},
+		{"limit above max page size", "/api/signals?offset=1&limit=100", http.StatusOK, 1, []int{1, 2, 3}},
+		{"last page", "/api/signals?offset=3", http.StatusOK, 3, []int{3}},
+		{"offset past the end", "/api/signals?offset=10", http.StatusOK, 4, []int{}},
+		{"huge offset", "/api/signals?offset=9223372036854775807&limit=3", http.StatusOK, 4, []int{}},
+		{"negative offset", "/api/signals?offset=-1", http.StatusBadRequest, 0, nil},
+		{"bad limit", "/api/signals?limit=x", http.StatusBadRequest, 0, nil},
+	}
+
+	for _, test := range tests {
+		w := testRequest(handler, http.MethodGet, test.target, "")
+		assert.Equal(t, test.status, w.Code, test.name)
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		page, ids := pageIds(t, w)
+		assert.Equal(t, 4, page.Total, test.name)
+		assert.Equal(t, test.offset, page.Offset, test.name)
+		assert.Equal(t, test.ids, ids, test.name)
+	}
+}
+
+func Test_Api_SignalsQuery(t *testing.T) {
+	handler := testApi()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		ids    []int
+	}{
+		{"query params", http.MethodGet, "/api/signals?brand=LG", "", http.StatusOK, []int{0, 1}},
+		{"filter body", http.MethodPost, "/api/signals", `{"function":"Power"}`, http.StatusOK, []int{0, 2, 3}},
+		{"query params and filter body", http.MethodPost, "/api/signals?brand=LG", `{"function":"Power"}`, http.StatusOK, []int{0}},
+		{"query params and filter param", http.MethodGet, `/api/signals?device=TV&filter={"function":"Power"}`, "", http.StatusOK, []int{0, 2}},
+		{"filter param wins over body", http.MethodPost, `/api/signals?filter={"brand":"Samsung"}`, `{"brand":"LG"}`, http.StatusOK, []int{3}},
+		{"empty body", http.MethodPost, "/api/signals?brand=Sony", " ", http.StatusOK, []int{2}},
+		{"bad filter body", http.MethodPost, "/api/signals", `{"brand":`, http.StatusBadRequest, nil},
+	}
+
+	for _, test := range tests {
+		w := testRequest(handler, test.method, test.target, test.body)
+		assert.Equal(t, test.status, w.Code, test.name)
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		page, ids := pageIds(t, w)
+		assert.Equal(t, len(test.ids), page.Total, test.name)
+		assert.Equal(t, test.ids, ids, test.name)
+	}
+}
+
+func Test_Api_Signal(t *testing.T) {
+	handler := testApi()
+
+	tests := []struct {
+		name     string
+		target   string
+		status   int
+		expanded bool
+		error    bool
+		file     string
+	}{
+		{"parsed signal is expanded", "/api/signals/0", http.StatusOK, true, false, "LG.ir.json"},
+		{"raw signal is kept", "/api/signals/1", http.StatusOK, false, false, "LG.ir.json"},
+		{"unknown protocol", "/api/signals/2", http.StatusOK, false, true, "Sony.ir.json"},
+		{"missing id", "/api/signals/4", http.StatusNotFound, false, false, ""},
+		{"bad id", "/api/signals/x", http.StatusNotFound, false, false, ""},
+	}
+
+	for _, test := range tests {
+		w := testRequest(handler, http.MethodGet, test.target, "")
+		assert.Equal(t, test.status, w.Code, test.name)
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		d := struct {
+			signalItem
+			Expanded bool   `json:"expanded"`
+			Error    string `json:"error"`
+		}{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &d), test.name)
+		assert.Equal(t, test.expanded, d.Expanded, test.name)
+		assert.Equal(t, test.error, d.Error != "", test.name)
+		assert.Equal(t, test.file, d.File, test.name)
+		if !test.error {
+			assert.NotEmpty(t, d.Signal.Data, test.name)
+			assert.NotZero(t, d.Signal.Frequency, test.name)
+		}
+	}
+}
+
+func Test_Api_Export(t *testing.T) {
+	handler := testApi()
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		body        string
+		status      int
+		contentType string
+		fileName    string
+		skipped     string
+		lines       int
+		prefix      string
+	}{
+		{"default json", http.MethodGet, "/api/export", "", http.StatusOK, "application/x-ndjson", "signals.json", "0", 4, "{"},
+		{"json", http.MethodGet, "/api/export?format=json&brand=LG", "", http.StatusOK, "application/x-ndjson", "signals.json", "0", 2, "{"},
+		{"ir", http.MethodPost, "/api/export?format=ir", `{"brand":"LG"}`, http.StatusOK, "text/plain; charset=utf-8", "signals.ir", "0", 0, "Filetype:"},
+		{"pronto skips undecodable", http.MethodGet, "/api/export?format=pronto", "", http.StatusOK, "text/plain; charset=utf-8", "signals.txt", "1", 6, "# "},
+		{"signal pronto", http.MethodGet, "/api/signals/0/export?format=pronto", "", http.StatusOK, "text/plain; charset=utf-8", "0.txt", "0", 2, "# "},
+		{"signal pronto undecodable", http.MethodGet, "/api/signals/2/export?format=pronto", "", http.StatusUnprocessableEntity, "", "", "", 0, ""},
+		{"bad format", http.MethodGet, "/api/export?format=xml", "", http.StatusBadRequest, "", "", "", 0, ""},
+	}
+
+	for _, test := range tests {
+		w := testRequest(handler, test.method, test.target, test.body)
+		assert.Equal(t, test.status, w.Code, test.name)
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		assert.Equal(t, test.contentType, w.Header().Get("Content-Type"), test.name)
+		assert.Equal(t, `attachment; filename="`+test.fileName+`"`, w.Header().Get("Content-Disposition"), test.name)
+		assert.Equal(t, test.skipped, w.Header().Get("X-Skipped-Signals"), test.name)
+		assert.True(t, strings.HasPrefix(w.Body.String(), test.prefix), test.name)
+
+		if test.lines != 0 {
+			lines := 0
+			scanner := bufio.NewScanner(bytes.NewReader(w.Body.Bytes()))
+			for scanner.Scan() {
+				lines++
+			}
+			assert.Equal(t, test.lines, lines, test.name)
+		}
+	}
+}
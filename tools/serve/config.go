@@ -0,0 +1,78 @@
+package serve
+
+import (
+	"net"
+	"path/filepath"
+
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	defaultListen      = "127.0.0.1:8080"
+	defaultMaxPageSize = 1000
+)
+
+type Config struct {
+	Source      string `json:"source" doc:"signals folder, stream file or catalog to serve"`
+	Listen      string `json:"listen" doc:"loopback address to listen on, 127.0.0.1:8080 by default"`
+	MaxPageSize int    `json:"maxPageSize" doc:"max signals per page, 1000 by default"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckRequiredString(this.Source, "source")
+		if this.Listen != "" {
+			errs.ThrowIf(validateLocalAddress(this.Listen))
+		}
+		errs.ThrowCheckNotNegative(this.MaxPageSize, "maxPageSize")
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Source, err = filepath.Abs(this.Source)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	if this.Listen == "" {
+		this.Listen = defaultListen
+	}
+
+	if this.MaxPageSize == 0 {
+		this.MaxPageSize = defaultMaxPageSize
+	}
+
+	return this, nil
+}
+
+func validateLocalAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errs.Errorf("listen: %w", err)
+	}
+
+	if host == "localhost" {
+		return nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return errs.Errorf("listen: expected loopback address, got '%s'", host)
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,67 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"irptools/signals/tree"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "SERVE", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	l := logs.L(ctx)
+	l.I("source <-: %s", cfg.Source)
+
+	t, err := tree.Load(ctx, cfg.Source)
+	if err != nil {
+		return errs.Errorf("failed to load signals: %w", err)
+	}
+	l.I("signals count = %v", len(t.Entries))
+
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	server := &http.Server{
+		Handler:           newApi(ctx, t, cfg.MaxPageSize).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	l.I("listening on http://%s", listener.Addr())
+
+	select {
+	case err = <-served:
+	case <-ctx.Done():
+		l.I("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = errs.Join(server.Shutdown(shutdownCtx), <-served)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return errs.Wrap(err)
+}
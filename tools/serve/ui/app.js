@@ -0,0 +1,230 @@
+"use strict";
+
+const state = {
+    brand: null,
+    device: null,
+    function: null,
+    remote: [],
+};
+
+const $ = (id) => document.getElementById(id);
+
+async function api(path, init) {
+    const resp = await fetch("api/" + path, init);
+    if (!resp.ok) {
+        const body = await resp.json().catch(() => ({error: resp.statusText}));
+        throw new Error(body.error);
+    }
+    return resp;
+}
+
+async function getJson(path) {
+    return (await api(path)).json();
+}
+
+function query(params) {
+    const q = new URLSearchParams();
+    for (const [k, v] of Object.entries(params)) {
+        if (v !== null) {
+            q.set(k, v);
+        }
+    }
+    return q.toString();
+}
+
+function status(text) {
+    $("status").textContent = text;
+}
+
+function fail(err) {
+    status("error: " + err.message);
+}
+
+function label(value) {
+    return value === "" ? "(empty)" : value;
+}
+
+function fillList(list, items, render, onSelect) {
+    list.replaceChildren();
+    for (const item of items) {
+        const li = document.createElement("li");
+        render(li, item);
+        li.addEventListener("click", () => {
+            for (const other of list.children) {
+                other.classList.remove("selected");
+            }
+            li.classList.add("selected");
+            onSelect(item);
+        });
+        list.appendChild(li);
+    }
+}
+
+function renderValue(li, item) {
+    const value = document.createElement("span");
+    value.textContent = label(item.value);
+    const count = document.createElement("span");
+    count.className = "count";
+    count.textContent = item.count;
+    li.append(value, count);
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+let brands = [];
+
+async function loadBrands() {
+    brands = await getJson("brands");
+    showBrands();
+    status(brands.length + " brands");
+}
+
+function showBrands() {
+    const search = $("brands-search").value.toLowerCase();
+    const items = brands.filter((b) => b.value.toLowerCase().includes(search));
+    fillList($("brands"), items, renderValue, (item) => selectBrand(item.value).catch(fail));
+}
+
+async function selectBrand(brand) {
+    Object.assign(state, {brand: brand, device: null, function: null});
+    const devices = await getJson("devices?" + query({brand: brand}));
+    fillList($("devices"), devices, renderValue, (item) => selectDevice(item.value).catch(fail));
+    $("functions").replaceChildren();
+    $("signals").replaceChildren();
+    $("details").hidden = true;
+}
+
+async function selectDevice(device) {
+    Object.assign(state, {device: device, function: null});
+    const functions = await getJson("functions?" + query({brand: state.brand, device: device}));
+    fillList($("functions"), functions, renderValue, (item) => selectFunction(item.value).catch(fail));
+    await loadSignals();
+}
+
+async function selectFunction(fn) {
+    state.function = fn;
+    await loadSignals();
+}
+
+async function loadSignals() {
+    const page = await getJson("signals?" + query({brand: state.brand, device: state.device, function: state.function}));
+    fillList($("signals"), page.items, renderSignal, (item) => showSignal(item.id).catch(fail));
+    $("details").hidden = true;
+    status(page.total + " signals" + (page.total > page.items.length ? ", showing " + page.items.length : ""));
+}
+
+function renderSignal(li, item) {
+    const s = item.signal;
+    const name = document.createElement("span");
+    name.textContent = label(s.function) + " " + (s.protocol || "raw");
+    const hint = document.createElement("span");
+    hint.className = "hint";
+    hint.textContent = item.file;
+    const add = document.createElement("button");
+    add.textContent = "+";
+    add.title = "add to remote";
+    add.addEventListener("click", (e) => {
+        e.stopPropagation();
+        addButton(item);
+    });
+    li.append(name, hint, add);
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+async function showSignal(id) {
+    const d = await getJson("signals/" + id);
+    $("details").hidden = false;
+    drawWaveform($("waveform"), d.signal.data || []);
+    const info = Object.assign({}, d.signal);
+    delete info.data;
+    if (d.error) {
+        info.error = d.error;
+    }
+    info.expanded = d.expanded;
+    $("info").textContent = JSON.stringify(info, null, 2);
+}
+
+function drawWaveform(canvas, data) {
+    const ctx = canvas.getContext("2d");
+    canvas.width = canvas.clientWidth;
+    ctx.clearRect(0, 0, canvas.width, canvas.height);
+    if (data.length === 0) {
+        ctx.fillText("no timings", 8, canvas.height / 2);
+        return;
+    }
+
+    const total = data.reduce((a, b) => a + b, 0);
+    const scale = (canvas.width - 2) / total;
+    const high = 10, low = canvas.height - 10;
+
+    ctx.beginPath();
+    ctx.moveTo(1, low);
+    let x = 1;
+    data.forEach((d, i) => {
+        const y = i % 2 === 0 ? high : low;
+        ctx.lineTo(x, y);
+        x += d * scale;
+        ctx.lineTo(x, y);
+    });
+    ctx.lineTo(x, low);
+    ctx.strokeStyle = "#33c";
+    ctx.stroke();
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+function addButton(item) {
+    state.remote.push({id: item.id, name: item.signal.function, brand: item.signal.brand});
+    showRemote();
+}
+
+function showRemote() {
+    const list = $("remote");
+    list.replaceChildren();
+    state.remote.forEach((b, i) => {
+        const li = document.createElement("li");
+        const name = document.createElement("input");
+        name.value = b.name;
+        name.title = b.brand;
+        name.addEventListener("change", () => b.name = name.value);
+        const remove = document.createElement("button");
+        remove.textContent = "−";
+        remove.title = "remove";
+        remove.addEventListener("click", () => {
+            state.remote.splice(i, 1);
+            showRemote();
+        });
+        li.append(name, remove);
+        list.appendChild(li);
+    });
+    $("remote-export").disabled = state.remote.length === 0;
+    $("remote-clear").disabled = state.remote.length === 0;
+}
+
+async function exportRemote() {
+    const name = $("remote-name").value || "remote";
+    const resp = await api("remote", {
+        method: "POST",
+        headers: {"Content-Type": "application/json"},
+        body: JSON.stringify({name: name, buttons: state.remote.map((b) => ({id: b.id, name: b.name}))}),
+    });
+    const url = URL.createObjectURL(await resp.blob());
+    const a = document.createElement("a");
+    a.href = url;
+    a.download = name + ".ir";
+    a.click();
+    URL.revokeObjectURL(url);
+    status("exported " + state.remote.length + " buttons");
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+$("brands-search").addEventListener("input", showBrands);
+$("remote-export").addEventListener("click", () => exportRemote().catch(fail));
+$("remote-clear").addEventListener("click", () => {
+    state.remote = [];
+    showRemote();
+});
+
+loadBrands().catch(fail);
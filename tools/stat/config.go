@@ -8,15 +8,16 @@ import (
 	"irptools/utils/misc"
 )
 
-func LoadConfig(filePath string) (Config, error) {
-	return misc.LoadJsonConfigFromFile(filePath, func(cfg Config) (Config, error) {
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
 		return cfg.Adjust()
 	})
 }
 
 type Config struct {
-	Source string             `json:"source"`
-	Target utils.TargetFolder `json:"target"`
+	Source string             `json:"source" doc:"folder with parsed signals"`
+	Target utils.TargetFolder `json:"target" doc:"where to write the stat"`
+	Html   bool               `json:"html" doc:"also write stat.html"`
 }
 
 func (this Config) Validate() error {
@@ -0,0 +1,194 @@
+package stat
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+
+	"irptools/utils/alg"
+	"irptools/utils/errs"
+)
+
+//go:embed report.html.tmpl
+var htmlReportTemplate string
+
+type htmlRow struct {
+	Label string
+	Count int
+}
+
+type htmlList struct {
+	Title string
+	Rows  []htmlRow
+}
+
+type htmlNum struct {
+	Metric string
+	Group  string
+	Num    Num
+}
+
+type htmlSource struct {
+	Name string
+	sourceStat
+}
+
+type htmlBrand struct {
+	Id        string
+	Name      string
+	Count     int
+	Protocols []htmlRow
+	Devices   []htmlRow
+	Functions []htmlRow
+}
+
+type htmlBar struct {
+	Label  string
+	Count  int
+	X      int
+	Y      int
+	W      int
+	H      int
+	LabelX int
+	CountX int
+	TextY  int
+}
+
+type htmlChart struct {
+	Title  string
+	Width  int
+	Height int
+	Bars   []htmlBar
+}
+
+type htmlReport struct {
+	Title   string
+	Ints    []htmlRow
+	Charts  []htmlChart
+	Nums    []htmlNum
+	Sources []htmlSource
+	Brands  []htmlBrand
+	Lists   []htmlList
+}
+
+func storeHtmlReport(filePath string, title string, stat Item) error {
+	tmpl, err := template.New("report").Parse(htmlReportTemplate)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = tmpl.Execute(f, newHtmlReport(title, stat))
+	return errs.Join(errs.Wrap(err), f.Close())
+}
+
+func newHtmlReport(title string, stat Item) htmlReport {
+	r := htmlReport{
+		Title: title,
+		Ints:  sortedRows(stat.Ints, false),
+		Charts: []htmlChart{
+			newHtmlChart("Protocols", stat.Strs["Protocols"]),
+			newHtmlChart("Frequencies", stat.Strs["Frequencies"]),
+		},
+	}
+
+	for _, metric := range sortedKeys(stat.Nums) {
+		r.Nums = append(r.Nums, htmlNum{Metric: metric, Num: stat.Nums[metric]})
+	}
+	for _, metric := range sortedKeys(stat.NumsBy) {
+		groups := stat.NumsBy[metric]
+		for _, group := range sortedKeys(groups) {
+			r.Nums = append(r.Nums, htmlNum{Metric: metric, Group: group, Num: groups[group]})
+		}
+	}
+
+	sources := sourcesStat(stat)
+	for _, name := range sortedKeys(sources) {
+		r.Sources = append(r.Sources, htmlSource{Name: name, sourceStat: sources[name]})
+	}
+
+	for i, brand := range sortedRows(stat.Strs["Brands"], true) {
+		r.Brands = append(r.Brands, htmlBrand{
+			Id:        fmt.Sprintf("brand-%d", i),
+			Name:      brand.Label,
+			Count:     brand.Count,
+			Protocols: sortedRows(stat.Matrices["BrandsProtocols"][brand.Label], true),
+			Devices:   sortedRows(stat.Matrices["BrandsDevices"][brand.Label], true),
+			Functions: sortedRows(stat.Matrices["BrandsFunctions"][brand.Label], true),
+		})
+	}
+
+	for _, key := range sortedKeys(stat.Strs) {
+		r.Lists = append(r.Lists, htmlList{Title: key, Rows: sortedRows(stat.Strs[key], true)})
+	}
+
+	return r
+}
+
+func newHtmlChart(title string, values map[string]int) htmlChart {
+	const (
+		labelWidth = 120
+		barsWidth  = 320
+		barHeight  = 16
+		barGap     = 4
+		countWidth = 60
+	)
+
+	rows := sortedRows(values, true)
+	maxCount := 1
+	for _, row := range rows {
+		maxCount = max(maxCount, row.Count)
+	}
+
+	chart := htmlChart{
+		Title:  title,
+		Width:  labelWidth + barsWidth + countWidth,
+		Height: len(rows)*(barHeight+barGap) + barGap,
+	}
+	for i, row := range rows {
+		y := barGap + i*(barHeight+barGap)
+		w := max(1, row.Count*barsWidth/maxCount)
+		label := row.Label
+		if label == "" {
+			label = "(empty)"
+		}
+		chart.Bars = append(chart.Bars, htmlBar{
+			Label:  label,
+			Count:  row.Count,
+			X:      labelWidth,
+			Y:      y,
+			W:      w,
+			H:      barHeight,
+			LabelX: labelWidth - 6,
+			CountX: labelWidth + w + 6,
+			TextY:  y + barHeight - 4,
+		})
+	}
+
+	return chart
+}
+
+func sortedRows(values map[string]int, byCount bool) []htmlRow {
+	rows := make([]htmlRow, 0, len(values))
+	for _, k := range sortedKeys(values) {
+		rows = append(rows, htmlRow{Label: k, Count: values[k]})
+	}
+	if byCount {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].Count > rows[j].Count
+		})
+	}
+	return rows
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := alg.MapKeys(m)
+	sort.Strings(keys)
+	return keys
+}
@@ -2,14 +2,20 @@ package stat
 
 func NewItem() Item {
 	return Item{
-		Ints: map[string]int{},
-		Strs: map[string]map[string]int{},
+		Ints:     map[string]int{},
+		Strs:     map[string]map[string]int{},
+		Nums:     map[string]Num{},
+		NumsBy:   map[string]map[string]Num{},
+		Matrices: map[string]map[string]map[string]int{},
 	}
 }
 
 type Item struct {
-	Ints map[string]int
-	Strs map[string]map[string]int
+	Ints     map[string]int
+	Strs     map[string]map[string]int
+	Nums     map[string]Num
+	NumsBy   map[string]map[string]Num
+	Matrices map[string]map[string]map[string]int
 }
 
 func (this *Item) IncInt(key string, value int) {
@@ -29,6 +35,41 @@ func (this *Item) addStrN(key string, value string, n int) {
 	}
 }
 
+func (this *Item) AddNum(key string, value int) {
+	num := this.Nums[key]
+	num.Add(value)
+	this.Nums[key] = num
+}
+
+func (this *Item) AddNumBy(key string, group string, value int) {
+	this.addNumBy(key, group, Num{Count: 1, Sum: value, Min: value, Max: value})
+}
+
+func (this *Item) addNumBy(key string, group string, value Num) {
+	nums, ok := this.NumsBy[key]
+	if !ok {
+		nums = map[string]Num{}
+		this.NumsBy[key] = nums
+	}
+	num := nums[group]
+	num.AddNum(value)
+	nums[group] = num
+}
+
+func (this *Item) IncCell(key string, row string, col string, n int) {
+	matrix, ok := this.Matrices[key]
+	if !ok {
+		matrix = map[string]map[string]int{}
+		this.Matrices[key] = matrix
+	}
+	cols, ok := matrix[row]
+	if !ok {
+		cols = map[string]int{}
+		matrix[row] = cols
+	}
+	cols[col] += n
+}
+
 func (this *Item) AddItem(item Item) {
 	for k, v := range item.Ints {
 		this.IncInt(k, v)
@@ -38,4 +79,50 @@ func (this *Item) AddItem(item Item) {
 			this.addStrN(k, kk, vv)
 		}
 	}
+	for k, v := range item.Nums {
+		num := this.Nums[k]
+		num.AddNum(v)
+		this.Nums[k] = num
+	}
+	for k, v := range item.NumsBy {
+		for kk, vv := range v {
+			this.addNumBy(k, kk, vv)
+		}
+	}
+	for k, v := range item.Matrices {
+		for row, cols := range v {
+			for col, n := range cols {
+				this.IncCell(k, row, col, n)
+			}
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Num struct {
+	Count int
+	Sum   int
+	Min   int
+	Max   int
+	Mean  float64
+}
+
+func (this *Num) Add(value int) {
+	this.AddNum(Num{Count: 1, Sum: value, Min: value, Max: value})
+}
+
+func (this *Num) AddNum(other Num) {
+	if other.Count == 0 {
+		return
+	}
+	if this.Count == 0 || other.Min < this.Min {
+		this.Min = other.Min
+	}
+	if this.Count == 0 || other.Max > this.Max {
+		this.Max = other.Max
+	}
+	this.Count += other.Count
+	this.Sum += other.Sum
+	this.Mean = float64(this.Sum) / float64(this.Count)
 }
@@ -3,10 +3,12 @@ package stat
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 
 	"irptools/signals/signal"
 	signalutils "irptools/signals/utils"
@@ -37,26 +39,48 @@ func execMain(ctx context.Context, cfg Config) error {
 		return errs.Wrap(err)
 	}
 
-	stat, err := collectSignalsStat(ctx, cfg.Source)
+	stat, err := Collect(ctx, cfg.Source)
 	if err != nil {
 		return errs.Wrap(err)
 	}
 
-	err = storeData(filepath.Join(cfg.Target.Path, "all.json"), stat)
+	return Store(ctx, cfg.Target, "Signals statistics: "+cfg.Source, stat, cfg.Html)
+}
+
+func Store(ctx context.Context, target utils.TargetFolder, title string, stat Item, html bool) error {
+	err := storeData(filepath.Join(target.Path, "all.json"), stat)
 	if err != nil {
 		return errs.Errorf("failed to store all: %w", err)
 	}
 
-	err = storeData(filepath.Join(cfg.Target.Path, "ints.json"), stat.Ints)
+	err = storeData(filepath.Join(target.Path, "ints.json"), stat.Ints)
 	if err != nil {
 		return errs.Errorf("failed to store ints: %w", err)
 	}
 
+	err = storeData(filepath.Join(target.Path, "nums.json"), map[string]interface{}{
+		"Nums":   stat.Nums,
+		"NumsBy": stat.NumsBy,
+	})
+	if err != nil {
+		return errs.Errorf("failed to store nums: %w", err)
+	}
+
+	err = storeData(filepath.Join(target.Path, "matrices.json"), stat.Matrices)
+	if err != nil {
+		return errs.Errorf("failed to store matrices: %w", err)
+	}
+
+	err = storeData(filepath.Join(target.Path, "sources.json"), sourcesStat(stat))
+	if err != nil {
+		return errs.Errorf("failed to store sources: %w", err)
+	}
+
 	for k, v := range stat.Strs {
 		fileName := k + ".json"
 		list := alg.MapKeys(v)
 		sort.Strings(list)
-		err = storeData(filepath.Join(cfg.Target.Path, fileName), []interface{}{
+		err = storeData(filepath.Join(target.Path, fileName), []interface{}{
 			map[string]int{"count": len(v)},
 			map[string]interface{}{k: v},
 			map[string]interface{}{"list": list},
@@ -67,7 +91,14 @@ func execMain(ctx context.Context, cfg Config) error {
 		}
 	}
 
-	logs.L(ctx).I("results -> %s", cfg.Target.Path)
+	if html {
+		err = storeHtmlReport(filepath.Join(target.Path, "report.html"), title, stat)
+		if err != nil {
+			return errs.Errorf("failed to store html report: %w", err)
+		}
+	}
+
+	logs.L(ctx).I("results -> %s", target.Path)
 
 	return nil
 }
@@ -89,31 +120,134 @@ func storeData(filePath string, data interface{}) error {
 type statCollector struct {
 	rootStat *Item
 	stat     Item
+	source   string
 }
 
 func (this *statCollector) Consume(s signal.Signal) error {
 	this.stat.IncInt("Signals", 1)
 	this.stat.AddStr("Brands", s.Brand)
 	this.stat.AddStr("Devices", s.Device)
+	this.stat.AddStr("Models", s.Model)
 	this.stat.AddStr("Functions", s.Function)
 	this.stat.AddStr("Protocols", s.Protocol)
 	this.stat.AddStr("Frequencies", strconv.Itoa(int(s.Frequency)))
+
+	kind, protocol := statKindParsed, s.Protocol
+	if s.Protocol == "" {
+		kind, protocol = statKindRaw, statKindRaw
+	}
+
+	this.stat.IncCell("BrandsProtocols", s.Brand, protocol, 1)
+	this.stat.IncCell("BrandsDevices", s.Brand, s.Device, 1)
+	this.stat.IncCell("BrandsFunctions", s.Brand, s.Function, 1)
+	this.stat.IncCell("DevicesFrequencies", s.Device, strconv.Itoa(int(s.Frequency)), 1)
+	this.stat.IncCell("SourcesKinds", this.source, kind, 1)
+
+	if len(s.Data) == 0 {
+		return nil
+	}
+
+	duration, pulses := 0, s.Data.Pulses()
+	for i, d := range s.Data {
+		duration += int(d)
+		if i%2 == 0 {
+			this.stat.AddNumBy("MarksByProtocol", protocol, int(d))
+		} else {
+			this.stat.AddNumBy("SpacesByProtocol", protocol, int(d))
+		}
+	}
+
+	this.stat.AddNum("Duration", duration)
+	this.stat.AddNum("Pulses", pulses)
+	this.stat.AddStr("DurationsMs", durationBucket(duration))
+	this.stat.AddStr("PulseCounts", strconv.Itoa(pulses))
+	this.stat.AddNumBy("DurationByProtocol", protocol, duration)
+	this.stat.AddNumBy("PulsesByProtocol", protocol, pulses)
 	return nil
 }
 
+func durationBucket(duration int) string {
+	const bucketMs = 10
+	from := duration / 1000 / bucketMs * bucketMs
+	return fmt.Sprintf("%04d-%04d", from, from+bucketMs)
+}
+
+const (
+	statKindRaw    = "raw"
+	statKindParsed = "parsed"
+)
+
 func (this *statCollector) Close() error {
 	this.stat.IncInt("Files", 1)
 	this.rootStat.AddItem(this.stat)
 	return nil
 }
 
-func collectSignalsStat(ctx context.Context, sourcePath string) (Item, error) {
-	rootStat := NewItem()
-	err := signalutils.EnumSignals(ctx, sourcePath, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
-		return &statCollector{rootStat: &rootStat, stat: NewItem()}, nil
-	})
+func LoadItem(filePath string) (Item, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return Item{}, errs.Wrap(err)
+	}
+
+	item := NewItem()
+	err = json.Unmarshal(data, &item)
+	return item, errs.Wrap(err)
+}
+
+func Collect(ctx context.Context, sourcePath string) (Item, error) {
+	collector := NewCollector(sourcePath)
+	err := signalutils.EnumSignals(ctx, sourcePath, collector.NewConsumer)
+	if err != nil {
+		return collector.Stat(), errs.Wrap(err)
+	}
+	return collector.Stat(), nil
+}
+
+func NewCollector(rootPath string) *Collector {
+	return &Collector{rootPath: rootPath, stat: NewItem()}
+}
+
+type Collector struct {
+	rootPath string
+	stat     Item
+}
+
+func (this *Collector) NewConsumer(filePath string) (signalutils.ClosableSignalConsumer, error) {
+	return &statCollector{rootStat: &this.stat, stat: NewItem(), source: sourceOf(this.rootPath, filePath)}, nil
+}
+
+func (this *Collector) Stat() Item {
+	return this.stat
+}
+
+func sourceOf(rootPath string, filePath string) string {
+	relPath, err := filepath.Rel(rootPath, filePath)
 	if err != nil {
-		return rootStat, errs.Wrap(err)
+		return ""
+	}
+	source, _, found := strings.Cut(filepath.ToSlash(relPath), "/")
+	if !found {
+		return ""
+	}
+	return source
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type sourceStat struct {
+	Raw      int     `json:"raw"`
+	Parsed   int     `json:"parsed"`
+	RawRatio float64 `json:"rawRatio"`
+}
+
+func sourcesStat(stat Item) map[string]sourceStat {
+	result := map[string]sourceStat{}
+	for source, kinds := range stat.Matrices["SourcesKinds"] {
+		ss := sourceStat{Raw: kinds[statKindRaw], Parsed: kinds[statKindParsed]}
+		if ss.Raw+ss.Parsed != 0 {
+			ss.RawRatio = float64(ss.Raw) / float64(ss.Raw+ss.Parsed)
+		}
+		result[source] = ss
 	}
-	return rootStat, nil
+	return result
 }
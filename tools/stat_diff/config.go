@@ -0,0 +1,78 @@
+package stat_diff
+
+import (
+	"path/filepath"
+
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Old    string       `json:"old" doc:"old stat.json or folder"`
+	New    string       `json:"new" doc:"new stat.json or folder"`
+	Target TargetConfig `json:"target" doc:"where to write the diff"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckRequiredString(this.Old, "old")
+		errs.ThrowCheckRequiredString(this.New, "new")
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Old))
+		errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.New))
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Old, err = filepath.Abs(this.Old)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.New, err = filepath.Abs(this.New)
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder           utils.TargetFolder `json:"folder" doc:"output folder"`
+	MaxListedSignals int                `json:"maxListedSignals" doc:"max signals listed per change, 0 for all"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+		errs.ThrowCheckNotNegative(this.MaxListedSignals, "maxListedSignals")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
@@ -0,0 +1,277 @@
+package stat_diff
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"irptools/signals/signal"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/stat"
+	"irptools/tools/utils"
+	"irptools/utils/alg"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "STAT_DIFF", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	l.I("old    <-: %s", cfg.Old)
+	l.I("new    <-: %s", cfg.New)
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	oldRun, err := loadRun(ctx, cfg.Old)
+	if err != nil {
+		return errs.Errorf("failed to load old run: %w", err)
+	}
+
+	newRun, err := loadRun(ctx, cfg.New)
+	if err != nil {
+		return errs.Errorf("failed to load new run: %w", err)
+	}
+
+	d := diffRuns(oldRun, newRun, cfg.Target.MaxListedSignals)
+	d.Old, d.New = cfg.Old, cfg.New
+
+	err = utils.StoreJson(filepath.Join(cfg.Target.Folder.Path, "diff.json"), d)
+	if err != nil {
+		return errs.Errorf("failed to store diff: %w", err)
+	}
+
+	summary := d.Summary()
+	err = os.WriteFile(filepath.Join(cfg.Target.Folder.Path, "summary.txt"), []byte(summary), 0644)
+	if err != nil {
+		return errs.Errorf("failed to store summary: %w", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(summary, "\n"), "\n") {
+		l.I("%s", line)
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type run struct {
+	stat    stat.Item
+	signals map[string]signalRef
+}
+
+type signalRef struct {
+	Hash     string `json:"hash"`
+	Brand    string `json:"brand"`
+	Device   string `json:"device"`
+	Function string `json:"function"`
+	Protocol string `json:"protocol"`
+	File     string `json:"file"`
+}
+
+func loadRun(ctx context.Context, folderPath string) (run, error) {
+	statFilePath := filepath.Join(folderPath, "all.json")
+	if _, err := os.Stat(statFilePath); err == nil {
+		item, err := stat.LoadItem(statFilePath)
+		return run{stat: item}, errs.Wrap(err)
+	}
+
+	signalsPath := filepath.Join(folderPath, "result")
+	if _, exists := signalutils.ResolveSignalsPath(signalsPath); !exists {
+		signalsPath = folderPath
+	}
+
+	item, err := stat.Collect(ctx, signalsPath)
+	if err != nil {
+		return run{}, errs.Wrap(err)
+	}
+
+	r := run{stat: item, signals: map[string]signalRef{}}
+	err = signalutils.EnumSignals(ctx, signalsPath, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		relPath, _ := filepath.Rel(signalsPath, filePath)
+		return signalutils.NewNopClosingSignalConsumer(signalutils.SignalConsumerFn(func(s signal.Signal) error {
+			hash := signalutils.SignalContentHash(s)
+			r.signals[hash] = signalRef{
+				Hash:     hash,
+				Brand:    s.Brand,
+				Device:   s.Device,
+				Function: s.Function,
+				Protocol: s.Protocol,
+				File:     filepath.ToSlash(relPath),
+			}
+			return nil
+		})), nil
+	})
+
+	return r, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type countDelta struct {
+	Old   int `json:"old"`
+	New   int `json:"new"`
+	Delta int `json:"delta"`
+}
+
+type strsDiff struct {
+	Added   []string              `json:"added"`
+	Removed []string              `json:"removed"`
+	Changed map[string]countDelta `json:"changed"`
+}
+
+type signalsDiff struct {
+	Compared     bool        `json:"compared"`
+	AddedCount   int         `json:"addedCount"`
+	RemovedCount int         `json:"removedCount"`
+	Added        []signalRef `json:"added"`
+	Removed      []signalRef `json:"removed"`
+}
+
+type diff struct {
+	Old     string                `json:"old"`
+	New     string                `json:"new"`
+	Ints    map[string]countDelta `json:"ints"`
+	Strs    map[string]strsDiff   `json:"strs"`
+	Signals signalsDiff           `json:"signals"`
+}
+
+func diffRuns(oldRun, newRun run, maxListedSignals int) diff {
+	d := diff{
+		Ints: map[string]countDelta{},
+		Strs: map[string]strsDiff{},
+	}
+
+	for _, key := range unionKeys(oldRun.stat.Ints, newRun.stat.Ints) {
+		d.Ints[key] = newCountDelta(oldRun.stat.Ints[key], newRun.stat.Ints[key])
+	}
+
+	for _, key := range unionKeys(oldRun.stat.Strs, newRun.stat.Strs) {
+		d.Strs[key] = diffStrs(oldRun.stat.Strs[key], newRun.stat.Strs[key])
+	}
+
+	if oldRun.signals != nil && newRun.signals != nil {
+		d.Signals.Compared = true
+		d.Signals.Added = diffSignals(newRun.signals, oldRun.signals)
+		d.Signals.Removed = diffSignals(oldRun.signals, newRun.signals)
+		d.Signals.AddedCount = len(d.Signals.Added)
+		d.Signals.RemovedCount = len(d.Signals.Removed)
+		if maxListedSignals != 0 {
+			d.Signals.Added = d.Signals.Added[:min(maxListedSignals, len(d.Signals.Added))]
+			d.Signals.Removed = d.Signals.Removed[:min(maxListedSignals, len(d.Signals.Removed))]
+		}
+	}
+
+	return d
+}
+
+func diffStrs(oldValues, newValues map[string]int) strsDiff {
+	d := strsDiff{Added: []string{}, Removed: []string{}, Changed: map[string]countDelta{}}
+	for _, value := range unionKeys(oldValues, newValues) {
+		oldCount, inOld := oldValues[value]
+		newCount, inNew := newValues[value]
+		switch {
+		case !inOld:
+			d.Added = append(d.Added, value)
+		case !inNew:
+			d.Removed = append(d.Removed, value)
+		case oldCount != newCount:
+			d.Changed[value] = newCountDelta(oldCount, newCount)
+		}
+	}
+	return d
+}
+
+func diffSignals(from, other map[string]signalRef) []signalRef {
+	result := []signalRef{}
+	for hash, ref := range from {
+		if _, ok := other[hash]; !ok {
+			result = append(result, ref)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Hash < result[j].Hash
+	})
+	return result
+}
+
+func newCountDelta(oldCount, newCount int) countDelta {
+	return countDelta{Old: oldCount, New: newCount, Delta: newCount - oldCount}
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := alg.MapKeys(a)
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (this diff) Summary() string {
+	b := strings.Builder{}
+
+	ints := alg.MapKeys(this.Ints)
+	sort.Strings(ints)
+	for _, key := range ints {
+		c := this.Ints[key]
+		_, _ = fmt.Fprintf(&b, "%s: %d -> %d (%+d)\n", key, c.Old, c.New, c.Delta)
+	}
+
+	for _, key := range []string{"Brands", "Protocols", "Functions"} {
+		d, ok := this.Strs[key]
+		if !ok {
+			continue
+		}
+		_, _ = fmt.Fprintf(&b, "%s: +%d -%d ~%d\n", key, len(d.Added), len(d.Removed), len(d.Changed))
+		if len(d.Added) != 0 {
+			_, _ = fmt.Fprintf(&b, "  added: %s\n", joinValues(d.Added))
+		}
+		if len(d.Removed) != 0 {
+			_, _ = fmt.Fprintf(&b, "  removed: %s\n", joinValues(d.Removed))
+		}
+	}
+
+	if this.Signals.Compared {
+		_, _ = fmt.Fprintf(&b, "Signals by content: +%d -%d\n", this.Signals.AddedCount, this.Signals.RemovedCount)
+	} else {
+		b.WriteString("Signals by content: not compared, stat folders have no signals\n")
+	}
+
+	return b.String()
+}
+
+func joinValues(values []string) string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == "" {
+			value = "(empty)"
+		}
+		result = append(result, value)
+	}
+	return strings.Join(result, ", ")
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+
+	"irptools/utils/errs"
+)
+
+func StoreJson(filePath string, v any) error {
+	jsonData, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	return errs.Wrap(os.WriteFile(filePath, append(jsonData, '\n'), 0644))
+}
@@ -11,9 +11,9 @@ import (
 )
 
 type TargetFolder struct {
-	Path                string `json:"path"`
-	CleanupIfExists     bool   `json:"cleanupIfExists"`
-	WithoutCreationTime bool   `json:"withoutCreationTime"`
+	Path                string `json:"path" doc:"output folder"`
+	CleanupIfExists     bool   `json:"cleanupIfExists" doc:"remove the folder content if it already exists"`
+	WithoutCreationTime bool   `json:"withoutCreationTime" doc:"write directly into path instead of a timestamped subfolder"`
 }
 
 func (this TargetFolder) Validate() error {
@@ -0,0 +1,111 @@
+package verify
+
+import (
+	"path/filepath"
+
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/misc"
+)
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func LoadConfig(filePath string, overrides []misc.ConfigOverride) (Config, error) {
+	return misc.LoadJsonConfigFromFile(filePath, overrides, func(cfg Config) (Config, error) {
+		return cfg.Adjust()
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type Config struct {
+	Source string        `json:"source" doc:"folder with parsed signals"`
+	Golden string        `json:"golden" doc:"folder with golden signals"`
+	Target TargetConfig  `json:"target" doc:"where to write the report"`
+	Verify VerifyOptions `json:"verify" doc:"verification options"`
+}
+
+func (this Config) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Target, "target")
+		errs.ThrowCheckValid(this.Verify, "verify")
+		if this.Source == "" && this.Golden == "" {
+			errs.Throw(errs.Error("source or golden is required"))
+		}
+		if this.Source != "" {
+			errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Source))
+		}
+		if this.Golden != "" {
+			errs.ThrowIf(this.Target.Folder.ValidateSourcePath(this.Golden))
+		}
+	})
+}
+
+func (this Config) Adjust() (Config, error) {
+	var err error
+
+	this.Target, err = this.Target.Adjust()
+	if err != nil {
+		return this, errs.Wrap(err)
+	}
+
+	this.Verify = this.Verify.Adjust()
+
+	if this.Source != "" {
+		this.Source, err = filepath.Abs(this.Source)
+		if err != nil {
+			return this, errs.Wrap(err)
+		}
+	}
+
+	if this.Golden != "" {
+		this.Golden, err = filepath.Abs(this.Golden)
+		if err != nil {
+			return this, errs.Wrap(err)
+		}
+	}
+
+	return this, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TargetConfig struct {
+	Folder utils.TargetFolder `json:"folder" doc:"output folder"`
+}
+
+func (this TargetConfig) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckValid(this.Folder, "folder")
+	})
+}
+
+func (this TargetConfig) Adjust() (TargetConfig, error) {
+	var err error
+	this.Folder, err = this.Folder.Adjust()
+	return this, errs.Wrap(err)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type VerifyOptions struct {
+	ToleranceScale   float64 `json:"toleranceScale" doc:"scale of protocol timing tolerances"`
+	MaxDeviations    int     `json:"maxDeviations" doc:"max deviations listed per signal, 0 for all"`
+	FailOnDeviations bool    `json:"failOnDeviations" doc:"fail the command if any signal deviates"`
+}
+
+func (this VerifyOptions) Validate() error {
+	return errs.Catch(func() {
+		errs.ThrowCheckPositive(this.ToleranceScale, "toleranceScale")
+		errs.ThrowCheckNotNegative(this.MaxDeviations, "maxDeviations")
+	})
+}
+
+func (this VerifyOptions) Adjust() VerifyOptions {
+	if this.ToleranceScale == 0 {
+		this.ToleranceScale = 1
+	}
+	return this
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
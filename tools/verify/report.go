@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+)
+
+type report struct {
+	options    VerifyOptions
+	Pairs      counts            `json:"pairs"`
+	Golden     counts            `json:"golden"`
+	ByProtocol map[string]counts `json:"byProtocol"`
+	Entries    []reportEntry     `json:"entries"`
+}
+
+type counts struct {
+	Checked int `json:"checked"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+}
+
+func (this *counts) add(entry reportEntry) {
+	this.Checked++
+	if entry.Passed {
+		this.Passed++
+	} else {
+		this.Failed++
+	}
+}
+
+type reportEntry struct {
+	Kind              string          `json:"kind"`
+	Brand             string          `json:"brand"`
+	Device            string          `json:"device"`
+	Function          string          `json:"function"`
+	Protocol          string          `json:"protocol"`
+	Address           string          `json:"address"`
+	Command           string          `json:"command"`
+	Expected          string          `json:"expected,omitempty"`
+	Actual            string          `json:"actual"`
+	Passed            bool            `json:"passed"`
+	Error             string          `json:"error,omitempty"`
+	Confidence        float64         `json:"confidence"`
+	MaxDeviation      irp.Micros      `json:"maxDeviation"`
+	MeanDeviation     float64         `json:"meanDeviation"`
+	ExpectedFrequency irp.Frequency   `json:"expectedFrequency"`
+	ActualFrequency   irp.Frequency   `json:"actualFrequency"`
+	Deviations        []irp.Deviation `json:"deviations,omitempty"`
+}
+
+const (
+	entryKindPair   = "pair"
+	entryKindGolden = "golden"
+)
+
+func newReport(options VerifyOptions) *report {
+	return &report{
+		options:    options,
+		ByProtocol: map[string]counts{},
+	}
+}
+
+func newEntry(protocol string, code irp.SignalCode, raw signal.Signal) reportEntry {
+	return reportEntry{
+		Brand:           raw.Brand,
+		Device:          raw.Device,
+		Function:        raw.Function,
+		Protocol:        protocol,
+		Address:         irp.FormatHex32(code.Address),
+		Command:         irp.FormatHex32(code.Command),
+		Actual:          signalRef(raw),
+		ActualFrequency: raw.Frequency,
+	}
+}
+
+func (this *report) addPair(entry reportEntry) {
+	entry.Kind = entryKindPair
+	this.Pairs.add(entry)
+	this.add(entry)
+}
+
+func (this *report) addGolden(entry reportEntry) {
+	entry.Kind = entryKindGolden
+	this.Golden.add(entry)
+	this.add(entry)
+}
+
+func (this *report) add(entry reportEntry) {
+	c := this.ByProtocol[entry.Protocol]
+	c.add(entry)
+	this.ByProtocol[entry.Protocol] = c
+	this.Entries = append(this.Entries, entry)
+}
@@ -0,0 +1,212 @@
+package verify
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"irptools/signals/irp"
+	"irptools/signals/signal"
+	"irptools/signals/sources/fz"
+	signalutils "irptools/signals/utils"
+	"irptools/tools/utils"
+	"irptools/utils/errs"
+	"irptools/utils/logs"
+)
+
+func Main(ctx context.Context, cfg Config) error {
+	return utils.DecorateExecution(ctx, "VERIFY", func(ctx context.Context) error {
+		return execMain(ctx, cfg)
+	})
+}
+
+func execMain(ctx context.Context, cfg Config) error {
+	err := errs.CheckValid(cfg, "config")
+	if err != nil {
+		return err
+	}
+
+	cfg.Target.Folder, err = cfg.Target.Folder.PrepareTarget()
+	if err != nil {
+		return errs.Errorf("failed to prepare target: %w", err)
+	}
+
+	l := logs.L(ctx)
+	if cfg.Source != "" {
+		l.I("source <-: %s", cfg.Source)
+	}
+	if cfg.Golden != "" {
+		l.I("golden <-: %s", cfg.Golden)
+	}
+	l.I("target ->: %s", cfg.Target.Folder.Path)
+
+	r := newReport(cfg.Verify)
+
+	if cfg.Source != "" {
+		err = verifyPairs(ctx, cfg.Source, r)
+		if err != nil {
+			return errs.Errorf("failed to verify pairs: %w", err)
+		}
+	}
+
+	if cfg.Golden != "" {
+		err = verifyGolden(ctx, cfg.Golden, r)
+		if err != nil {
+			return errs.Errorf("failed to verify golden corpus: %w", err)
+		}
+	}
+
+	err = utils.StoreJson(filepath.Join(cfg.Target.Folder.Path, "report.json"), r)
+	if err != nil {
+		return errs.Errorf("failed to store report: %w", err)
+	}
+
+	l.I("pairs: %v; golden: %v", r.Pairs, r.Golden)
+
+	if cfg.Verify.FailOnDeviations && r.Pairs.Failed+r.Golden.Failed > 0 {
+		return errs.Errorf("verification failed: %d pair(s) and %d golden signal(s) deviate", r.Pairs.Failed, r.Golden.Failed)
+	}
+
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type pairGroup struct {
+	parsed []signal.Signal
+	raw    []signal.Signal
+}
+
+func verifyPairs(ctx context.Context, source string, r *report) error {
+	groups := map[string]*pairGroup{}
+	var keys []string
+
+	collect := func(filePath string, s signal.Signal) error {
+		if s.Source == "" {
+			s.Source = filePath
+		}
+		key := strings.ToLower(s.Brand) + "\x00" + strings.ToLower(s.Function)
+		group, ok := groups[key]
+		if !ok {
+			group = &pairGroup{}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		if s.Protocol == "" {
+			group.raw = append(group.raw, s)
+		} else if len(s.Data) != 0 {
+			group.parsed = append(group.parsed, s)
+		}
+		return nil
+	}
+
+	err := signalutils.EnumSignals(ctx, source, func(filePath string) (signalutils.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(signalutils.SignalConsumerFn(func(s signal.Signal) error {
+			return collect(filePath, s)
+		})), nil
+	})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		if len(group.parsed) == 0 {
+			continue
+		}
+		for _, raw := range group.raw {
+			r.addPair(bestPair(group.parsed, raw, r.options))
+		}
+	}
+
+	return nil
+}
+
+func bestPair(parsed []signal.Signal, raw signal.Signal, options VerifyOptions) reportEntry {
+	var best reportEntry
+	for i, expected := range parsed {
+		entry := verifySignal(expected.Protocol, expected.Code, raw, options)
+		entry.Expected = signalRef(expected)
+		if i == 0 || entry.Confidence > best.Confidence {
+			best = entry
+		}
+	}
+	return best
+}
+
+func verifyGolden(ctx context.Context, golden string, r *report) error {
+	collect := signalutils.SignalConsumerFn(func(s signal.Signal) error {
+		if s.Protocol != "" {
+			return nil
+		}
+		protocol, ok := s.Meta[goldenMetaProtocol]
+		if !ok {
+			return nil
+		}
+
+		code := irp.SignalCode{}
+		address, err := irp.ParseHex32(s.Meta[goldenMetaAddress])
+		if err == nil {
+			code.Address = address
+			code.Command, err = irp.ParseHex32(s.Meta[goldenMetaCommand])
+		}
+		if err != nil {
+			entry := newEntry(protocol, code, s)
+			entry.Error = err.Error()
+			r.addGolden(entry)
+			return nil
+		}
+
+		r.addGolden(verifySignal(protocol, code, s, r.options))
+		return nil
+	})
+
+	_, err := fz.ParseIrFiles(ctx, golden, fz.Options{}, func(filePath string) (fz.ClosableSignalConsumer, error) {
+		return signalutils.NewNopClosingSignalConsumer(collect), nil
+	})
+	return errs.Wrap(err)
+}
+
+const (
+	goldenMetaProtocol = "protocol"
+	goldenMetaAddress  = "address"
+	goldenMetaCommand  = "command"
+)
+
+func verifySignal(protocol string, code irp.SignalCode, raw signal.Signal, options VerifyOptions) reportEntry {
+	entry := newEntry(protocol, code, raw)
+
+	p, err := irp.GetIrp(strings.ToLower(protocol))
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	expected, err := p.Decode(code)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	tolerances := irp.GetTolerances(protocol).Scaled(options.ToleranceScale)
+	cmp := irp.CompareFirstFrame(expected, raw.Data, p.MinSplitTime(), tolerances)
+
+	entry.ExpectedFrequency = p.Frequency()
+	entry.Confidence = cmp.Confidence()
+	entry.Passed = cmp.Matched == cmp.Compared
+	entry.MaxDeviation = cmp.MaxDeviation
+	entry.MeanDeviation = cmp.MeanDeviation
+	entry.Deviations = cmp.Deviations
+	if options.MaxDeviations != 0 && len(entry.Deviations) > options.MaxDeviations {
+		entry.Deviations = entry.Deviations[:options.MaxDeviations]
+	}
+
+	return entry
+}
+
+func signalRef(s signal.Signal) string {
+	if s.Source == "" {
+		return s.Id
+	}
+	return s.Source + "#" + s.Id
+}
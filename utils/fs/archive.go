@@ -0,0 +1,193 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"io"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"irptools/utils/errs"
+)
+
+var archiveExts = []string{".zip", ".tar.gz", ".tgz"}
+
+func IsArchivePath(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func OpenFS(root string) (iofs.FS, error) {
+	archivePath, innerPath, ok, err := splitArchivePath(root)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if !ok {
+		return os.DirFS(root), nil
+	}
+
+	fsys, err := openArchive(archivePath)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if innerPath == "." {
+		return fsys, nil
+	}
+	return iofs.Sub(fsys, innerPath)
+}
+
+func splitArchivePath(filePath string) (archivePath string, innerPath string, ok bool, err error) {
+	innerParts := []string{}
+	for p := filepath.Clean(filePath); ; p = filepath.Dir(p) {
+		if IsArchivePath(p) {
+			info, err := os.Stat(p)
+			if err == nil && info.Mode().IsRegular() {
+				innerPath = path.Join(innerParts...)
+				if innerPath == "" {
+					innerPath = "."
+				}
+				return p, innerPath, true, nil
+			}
+			if err != nil && !errors.Is(err, iofs.ErrNotExist) {
+				return "", "", false, errs.Wrap(err)
+			}
+		}
+
+		parent := filepath.Dir(p)
+		if parent == p {
+			return "", "", false, nil
+		}
+		innerParts = append([]string{filepath.Base(p)}, innerParts...)
+	}
+}
+
+type archive struct {
+	fsys  iofs.FS
+	close func() error
+}
+
+var (
+	archivesMutex sync.Mutex
+	archives      = map[string]archive{}
+)
+
+func openArchive(archivePath string) (iofs.FS, error) {
+	archivesMutex.Lock()
+	defer archivesMutex.Unlock()
+
+	if a, ok := archives[archivePath]; ok {
+		return a.fsys, nil
+	}
+
+	var a archive
+	var err error
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		a, err = openZip(archivePath)
+	} else {
+		a, err = openTarGz(archivePath)
+	}
+	if err != nil {
+		return nil, errs.Errorf("failed to open archive '%s': %w", archivePath, err)
+	}
+
+	archives[archivePath] = a
+	return a.fsys, nil
+}
+
+// closes archives opened by OpenFS and OpenReadOnlyFile, files opened from them must not be used afterwards
+func CloseArchives() error {
+	archivesMutex.Lock()
+	defer archivesMutex.Unlock()
+
+	allErrors := make([]error, 0)
+	for archivePath, a := range archives {
+		allErrors = append(allErrors, a.close())
+		delete(archives, archivePath)
+	}
+	return errs.Join(allErrors...)
+}
+
+func openZip(archivePath string) (archive, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return archive{}, errs.Wrap(err)
+	}
+	return archive{fsys: r, close: r.Close}, nil
+}
+
+// unpacks the entries into a temporary stored zip, so they are read from disk with random access
+func openTarGz(archivePath string) (archive, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return archive{}, errs.Wrap(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return archive{}, errs.Wrap(err)
+	}
+
+	tmp, err := os.CreateTemp("", "irptools-*.zip")
+	if err != nil {
+		return archive{}, errs.Wrap(err)
+	}
+	remove := func() error {
+		return os.Remove(tmp.Name())
+	}
+
+	err = unpackTar(gz, tmp)
+	err = errs.Join(err, tmp.Close())
+	if err != nil {
+		return archive{}, errs.Join(err, remove())
+	}
+
+	r, err := zip.OpenReader(tmp.Name())
+	if err != nil {
+		return archive{}, errs.Join(errs.Wrap(err), remove())
+	}
+
+	return archive{fsys: r, close: func() error { return errs.Join(r.Close(), remove()) }}, nil
+}
+
+func unpackTar(r io.Reader, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: hdr.ModTime})
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		_, err = io.Copy(w, tr)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+	}
+
+	return errs.Wrap(zw.Close())
+}
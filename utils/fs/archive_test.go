@@ -0,0 +1,148 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testArchiveFiles = map[string]string{
+	"a.ir":        "a",
+	"TVs/LG.ir":   "lg",
+	"TVs/Sony.ir": "sony",
+}
+
+func writeTestZip(t *testing.T, filePath string) {
+	f, err := os.Create(filePath)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for name, content := range testArchiveFiles {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+}
+
+func writeTestTarGz(t *testing.T, filePath string) {
+	f, err := os.Create(filePath)
+	assert.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "TVs/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for name, content := range testArchiveFiles {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "./" + name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+		_, err = tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	assert.NoError(t, f.Close())
+}
+
+func Test_Archive_SplitArchivePath(t *testing.T) {
+	root := t.TempDir()
+	writeTestZip(t, filepath.Join(root, "data.zip"))
+	writeTestTarGz(t, filepath.Join(root, "data.tar.gz"))
+	assert.NoError(t, os.Mkdir(filepath.Join(root, "dir.zip"), 0755))
+
+	tests := []struct {
+		path    string
+		archive string
+		inner   string
+		ok      bool
+	}{
+		{"data.zip", "data.zip", ".", true},
+		{"data.zip/TVs", "data.zip", "TVs", true},
+		{"data.zip/TVs/LG.ir", "data.zip", "TVs/LG.ir", true},
+		{"data.tar.gz/TVs/", "data.tar.gz", "TVs", true},
+		{"missing.zip/TVs", "", "", false},
+		{"dir.zip/TVs", "", "", false},
+		{"plain/TVs", "", "", false},
+	}
+
+	for _, test := range tests {
+		archivePath, innerPath, ok, err := splitArchivePath(filepath.Join(root, test.path))
+		assert.NoError(t, err, test.path)
+		assert.Equal(t, test.ok, ok, test.path)
+		if !test.ok {
+			continue
+		}
+		assert.Equal(t, filepath.Join(root, test.archive), archivePath, test.path)
+		assert.Equal(t, test.inner, innerPath, test.path)
+	}
+}
+
+func Test_Archive_OpenFS(t *testing.T) {
+	root := t.TempDir()
+	writeTestZip(t, filepath.Join(root, "data.zip"))
+	writeTestTarGz(t, filepath.Join(root, "data.tgz"))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "dir", "TVs"), 0755))
+	for name, content := range testArchiveFiles {
+		assert.NoError(t, os.WriteFile(filepath.Join(root, "dir", filepath.FromSlash(name)), []byte(content), 0644))
+	}
+	defer func() {
+		assert.NoError(t, CloseArchives())
+	}()
+
+	tests := []struct {
+		path  string
+		files map[string]string
+	}{
+		{"dir", testArchiveFiles},
+		{"data.zip", testArchiveFiles},
+		{"data.tgz", testArchiveFiles},
+		{"data.zip/TVs", map[string]string{"LG.ir": "lg", "Sony.ir": "sony"}},
+		{"data.tgz/TVs", map[string]string{"LG.ir": "lg", "Sony.ir": "sony"}},
+	}
+
+	for _, test := range tests {
+		fsys, err := OpenFS(filepath.Join(root, test.path))
+		assert.NoError(t, err, test.path)
+
+		files := map[string]string{}
+		err = iofs.WalkDir(fsys, ".", func(path string, d iofs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := iofs.ReadFile(fsys, path)
+			files[path] = string(data)
+			return err
+		})
+		assert.NoError(t, err, test.path)
+		assert.Equal(t, test.files, files, test.path)
+	}
+
+	data, err := ReadFile(filepath.Join(root, "data.tgz", "TVs", "LG.ir"))
+	assert.NoError(t, err)
+	assert.Equal(t, "lg", string(data))
+
+	_, err = ReadFile(filepath.Join(root, "data.zip", "missing.ir"))
+	assert.Error(t, err)
+}
+
+func Test_Archive_CloseArchives(t *testing.T) {
+	root := t.TempDir()
+	archivePath := filepath.Join(root, "data.tar.gz")
+	writeTestTarGz(t, archivePath)
+
+	_, err := OpenFS(archivePath)
+	assert.NoError(t, err)
+	assert.Contains(t, archives, archivePath)
+
+	assert.NoError(t, CloseArchives())
+	assert.Empty(t, archives)
+
+	data, err := ReadFile(filepath.Join(archivePath, "a.ir"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(data))
+	assert.NoError(t, CloseArchives())
+}
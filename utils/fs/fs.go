@@ -2,6 +2,8 @@ package fs
 
 import (
 	"context"
+	"io"
+	iofs "io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,14 +27,24 @@ func AdjustPathsSlash(paths []string) []string {
 }
 
 func EnumFilePaths(root string, consume FilesConsumer) error {
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	if info, err := os.Stat(root); err == nil && info.Mode().IsRegular() && !IsArchivePath(root) {
+		_, err = consume(AdjustPathSlash(root))
+		return errs.Wrap(err)
+	}
+
+	fsys, err := OpenFS(root)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	err = iofs.WalkDir(fsys, ".", func(path string, d iofs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if info.IsDir() {
+		if d.IsDir() {
 			return nil
 		}
-		next, err := consume(AdjustPathSlash(path))
+		next, err := consume(AdjustPathSlash(filepath.Join(root, filepath.FromSlash(path))))
 		if err != nil {
 			return errs.Wrap(err)
 		}
@@ -66,7 +78,19 @@ func EnumFilePathsWithExt(root string, ext string, consume FilesConsumer) error
 	return EnumFilePathsWithFilter(root, isExt, consume)
 }
 
-func OpenReadOnlyFile(filePath string) (*os.File, error) {
+func OpenReadOnlyFile(filePath string) (iofs.File, error) {
+	archivePath, innerPath, ok, err := splitArchivePath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		fsys, err := openArchive(archivePath)
+		if err != nil {
+			return nil, err
+		}
+		return fsys.Open(innerPath)
+	}
+
 	file, err := os.OpenFile(filePath, os.O_RDONLY, 0644)
 	if err != nil {
 		return nil, err
@@ -74,10 +98,25 @@ func OpenReadOnlyFile(filePath string) (*os.File, error) {
 	return file, nil
 }
 
+func ReadFile(filePath string) ([]byte, error) {
+	file, err := OpenReadOnlyFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return io.ReadAll(file)
+}
+
 func CreateWriteOnlyFile(filePath string) (*os.File, error) {
 	return os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_APPEND|os.O_WRONLY, 0644)
 }
 
+func OpenAppendOnlyFile(filePath string) (*os.File, error) {
+	return os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0644)
+}
+
 func GetFileSize(file *os.File) (uint64, error) {
 	stat, err := file.Stat()
 	if err != nil {
@@ -1,6 +1,7 @@
 package jsonutils
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"irptools/utils/errs"
@@ -17,3 +18,17 @@ func Cast(from interface{}, to interface{}) error {
 	}
 	return nil
 }
+
+func CastStrict(from interface{}, to interface{}) error {
+	jsonData, err := json.Marshal(from)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(to)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCastOptions struct {
+	Delimiter string `json:"delimiter"`
+	Strict    bool   `json:"strict"`
+}
+
+func Test_Cast_CastStrict(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     any
+		expected testCastOptions
+		isErr    bool
+	}{
+		{"nil", nil, testCastOptions{}, false},
+		{"map", map[string]any{"delimiter": ";", "strict": true}, testCastOptions{Delimiter: ";", Strict: true}, false},
+		{"struct", testCastOptions{Delimiter: ";"}, testCastOptions{Delimiter: ";"}, false},
+		{"unknown field", map[string]any{"delimiter": ";", "ext": ".csv"}, testCastOptions{}, true},
+		{"wrong type", map[string]any{"strict": "yes"}, testCastOptions{}, true},
+	}
+
+	for _, test := range tests {
+		actual := testCastOptions{}
+		err := CastStrict(test.from, &actual)
+		if test.isErr {
+			assert.Error(t, err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, actual, test.name)
+	}
+
+	lenient := testCastOptions{}
+	assert.NoError(t, Cast(map[string]any{"delimiter": ";", "ext": ".csv"}, &lenient))
+	assert.Equal(t, testCastOptions{Delimiter: ";"}, lenient)
+}
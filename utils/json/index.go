@@ -0,0 +1,145 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+type IndexLookupFn func(field string, values []any) (positions []int64, ok bool)
+
+// result is a superset of matching positions, the predicate still has to be evaluated over it
+func LookupIndexed(rules map[string]any, lookup IndexLookupFn) ([]int64, bool) {
+	children := make([]any, 0, len(rules))
+	for pred, arg := range rules {
+		children = append(children, map[string]any{pred: arg})
+	}
+	return lookupAnd(children, lookup)
+}
+
+func lookupRule(rule any, lookup IndexLookupFn) ([]int64, bool) {
+	m, ok := rule.(map[string]any)
+	if !ok || len(m) != 1 {
+		return nil, false
+	}
+
+	pred, arg := getKeyValue(m)
+	switch pred {
+	case PredAnd:
+		children, ok := arg.([]any)
+		if !ok {
+			return nil, false
+		}
+		return lookupAnd(children, lookup)
+	case PredOr:
+		children, ok := arg.([]any)
+		if !ok {
+			return nil, false
+		}
+		return lookupOr(children, lookup)
+	case PredTrue, PredFalse, PredEq, PredIn, PredNot:
+		return nil, false
+	}
+
+	return lookupField(pred, arg, lookup)
+}
+
+func lookupAnd(children []any, lookup IndexLookupFn) ([]int64, bool) {
+	var result []int64
+	found := false
+	for _, child := range children {
+		positions, ok := lookupRule(child, lookup)
+		if !ok {
+			continue
+		}
+		if !found {
+			result, found = positions, true
+			continue
+		}
+		result = intersectPositions(result, positions)
+	}
+	return result, found
+}
+
+func lookupOr(children []any, lookup IndexLookupFn) ([]int64, bool) {
+	var result []int64
+	for _, child := range children {
+		positions, ok := lookupRule(child, lookup)
+		if !ok {
+			return nil, false
+		}
+		result = unitePositions(result, positions)
+	}
+	return result, len(children) != 0
+}
+
+func lookupField(field string, rule any, lookup IndexLookupFn) ([]int64, bool) {
+	var values []any
+	if m, ok := rule.(map[string]any); ok {
+		if len(m) != 1 {
+			return nil, false
+		}
+		pred, arg := getKeyValue(m)
+		switch pred {
+		case PredEq:
+			values = []any{arg}
+		case PredIn:
+			arr, ok := arg.([]any)
+			if !ok {
+				return nil, false
+			}
+			values = arr
+		default:
+			return nil, false
+		}
+	} else {
+		values = []any{rule}
+	}
+
+	for _, value := range values {
+		if !isScalar(value) {
+			return nil, false
+		}
+	}
+
+	return lookup(field, values)
+}
+
+func isScalar(value any) bool {
+	switch value.(type) {
+	case string, bool, float64, json.Number, int, int64, uint32:
+		return true
+	}
+	return false
+}
+
+func intersectPositions(a, b []int64) []int64 {
+	result := make([]int64, 0, min(len(a), len(b)))
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+func unitePositions(a, b []int64) []int64 {
+	result := make([]int64, 0, len(a)+len(b))
+	result = append(result, a...)
+	result = append(result, b...)
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+
+	unique := result[:0]
+	for i, p := range result {
+		if i == 0 || p != result[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	return unique
+}
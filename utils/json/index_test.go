@@ -0,0 +1,119 @@
+package jsonutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testIndexedRow struct {
+	brand     string
+	function  string
+	frequency int
+	comment   string
+}
+
+var testIndexedRows = []testIndexedRow{
+	{"LG", "Power", 38000, ""},
+	{"LG", "Mute", 38000, "old"},
+	{"Sony", "Power", 40000, ""},
+	{"Sony", "Vol+", 40000, "old"},
+	{"Samsung", "Power", 38000, ""},
+	{"", "Power", 36000, ""},
+}
+
+func (this testIndexedRow) object() *MappedObject {
+	obj := NewMappedObject(map[string]func() (any, error){
+		"brand":     func() (any, error) { return this.brand, nil },
+		"function":  func() (any, error) { return this.function, nil },
+		"frequency": func() (any, error) { return this.frequency, nil },
+		"comment":   func() (any, error) { return this.comment, nil },
+	})
+	return &obj
+}
+
+// comment is not indexed, the same way not every signal field is indexed in a catalog
+func testIndexLookup(field string, values []any) ([]int64, bool) {
+	if field == "comment" {
+		return nil, false
+	}
+
+	positions := []int64{}
+	for i, row := range testIndexedRows {
+		value, _ := row.object().Field(field)
+		for _, v := range values {
+			if fmt.Sprintf("%v", value) == fmt.Sprintf("%v", v) {
+				positions = append(positions, int64(i))
+				break
+			}
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+	return positions, true
+}
+
+func Test_Index_LookupIsSupersetOfPredicate(t *testing.T) {
+	tests := []struct {
+		rules   string
+		indexed bool
+	}{
+		{`{"brand":"LG"}`, true},
+		{`{"brand":{"$eq":"Sony"}}`, true},
+		{`{"brand":{"$in":["LG","Samsung"]}}`, true},
+		{`{"frequency":38000}`, true},
+		{`{"brand":"LG","function":"Power"}`, true},
+		{`{"brand":"LG","comment":"old"}`, true},
+		{`{"$and":[{"brand":"Sony"},{"comment":"old"}]}`, true},
+		{`{"$or":[{"brand":"LG"},{"function":"Vol+"}]}`, true},
+		{`{"$or":[{"brand":"LG"},{"$and":[{"brand":"Sony"},{"function":"Power"}]}]}`, true},
+		{`{"brand":"Nokia"}`, true},
+		{`{"comment":"old"}`, false},
+		{`{"$or":[{"brand":"LG"},{"comment":"old"}]}`, false},
+		{`{"$not":{"brand":"LG"}}`, false},
+		{`{"brand":{"$not":{"$eq":"LG"}}}`, false},
+		{`{"$true":{}}`, false},
+	}
+
+	for _, test := range tests {
+		rules := map[string]any{}
+		assert.NoError(t, json.Unmarshal([]byte(test.rules), &rules), test.rules)
+
+		pred, err := BuildPredicate(rules, DefaultLogic())
+		assert.NoError(t, err, test.rules)
+
+		matches := []int64{}
+		for i, row := range testIndexedRows {
+			if pred.Is(row.object()) {
+				matches = append(matches, int64(i))
+			}
+		}
+
+		positions, indexed := LookupIndexed(rules, testIndexLookup)
+		assert.Equal(t, test.indexed, indexed, test.rules)
+		if !indexed {
+			continue
+		}
+		assert.Subset(t, positions, matches, test.rules)
+	}
+}
+
+func Test_Index_Positions(t *testing.T) {
+	tests := []struct {
+		a, b      []int64
+		intersect []int64
+		unite     []int64
+	}{
+		{[]int64{}, []int64{}, []int64{}, []int64{}},
+		{[]int64{1, 3, 5}, []int64{}, []int64{}, []int64{1, 3, 5}},
+		{[]int64{1, 3, 5}, []int64{3, 4, 5}, []int64{3, 5}, []int64{1, 3, 4, 5}},
+		{[]int64{1, 2}, []int64{3, 4}, []int64{}, []int64{1, 2, 3, 4}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.intersect, intersectPositions(test.a, test.b), fmt.Sprint(test.a, test.b))
+		assert.Equal(t, test.unite, unitePositions(test.a, test.b), fmt.Sprint(test.a, test.b))
+	}
+}
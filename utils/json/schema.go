@@ -0,0 +1,173 @@
+package jsonutils
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+type Schema = map[string]any
+
+// implemented by structs where the schema of one field depends on the value of another, e.g. options keyed on type
+type SchemaVariants interface {
+	SchemaVariants() (discriminator string, field string, variants map[string]any)
+}
+
+func NewSchema(title string, v any) Schema {
+	schema := schemaOf(reflect.TypeOf(v))
+	schema["$schema"] = SchemaDraft
+	schema["title"] = title
+	if properties, ok := schema["properties"].(map[string]Schema); ok {
+		properties["$schema"] = Schema{"type": "string"}
+		properties["$doc"] = Schema{"type": "object", "additionalProperties": Schema{"type": "string"}}
+	}
+	return schema
+}
+
+func schemaOf(t reflect.Type) Schema {
+	if t == nil {
+		return Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Schema{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]Schema{}
+		addStructProperties(t, properties)
+		schema := Schema{"type": "object", "properties": properties, "additionalProperties": false}
+		if v, ok := reflect.Zero(t).Interface().(SchemaVariants); ok {
+			schema["oneOf"] = variantSchemas(v)
+		}
+		return schema
+	}
+
+	return Schema{}
+}
+
+func variantSchemas(v SchemaVariants) []Schema {
+	discriminator, field, variants := v.SchemaVariants()
+
+	keys := make([]string, 0, len(variants))
+	for key := range variants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	schemas := make([]Schema, 0, len(keys))
+	for _, key := range keys {
+		schemas = append(schemas, Schema{
+			"properties": Schema{
+				discriminator: Schema{"const": key},
+				field:         schemaOf(reflect.TypeOf(variants[key])),
+			},
+			"required": []string{discriminator},
+		})
+	}
+	return schemas
+}
+
+func addStructProperties(t reflect.Type, properties map[string]Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !isJsonField(field) {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addStructProperties(field.Type, properties)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaOf(field.Type)
+		if doc := field.Tag.Get("doc"); doc != "" {
+			properties[name]["description"] = doc
+		}
+	}
+}
+
+// field docs keyed by dotted json path, "[]" and "*" stand for slice items and map values
+func FieldDocs(v any) map[string]string {
+	docs := map[string]string{}
+	addFieldDocs(reflect.TypeOf(v), "", docs)
+	return docs
+}
+
+func addFieldDocs(t reflect.Type, prefix string, docs map[string]string) {
+	if t == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		addFieldDocs(t.Elem(), prefix, docs)
+	case reflect.Slice, reflect.Array:
+		addFieldDocs(t.Elem(), prefix+"[]", docs)
+	case reflect.Map:
+		addFieldDocs(t.Elem(), joinDocPath(prefix, "*"), docs)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !isJsonField(field) {
+				continue
+			}
+
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+				addFieldDocs(field.Type, prefix, docs)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			path := joinDocPath(prefix, name)
+			if doc := field.Tag.Get("doc"); doc != "" {
+				docs[path] = doc
+			}
+			addFieldDocs(field.Type, path, docs)
+		}
+	}
+}
+
+// fields of embedded unexported structs are promoted by encoding/json
+func isJsonField(field reflect.StructField) bool {
+	return field.IsExported() || field.Anonymous && field.Type.Kind() == reflect.Struct
+}
+
+func joinDocPath(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,116 @@
+package jsonutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testSchemaTarget struct {
+	Path   string   `json:"path" doc:"output folder"`
+	Pretty bool     `json:"pretty,omitempty"`
+	Skip   []string `json:"-"`
+}
+
+type testSchemaInline struct {
+	Name string `json:"name" doc:"source name"`
+}
+
+type testSchemaSource struct {
+	testSchemaInline
+	Type    string         `json:"type" doc:"source type"`
+	Options any            `json:"options" doc:"source options"`
+	Count   uint           `json:"count"`
+	Ratio   float64        `json:"ratio"`
+	Tags    map[string]int `json:"tags"`
+	hidden  string
+}
+
+type testSchemaCsvOptions struct {
+	Delimiter string `json:"delimiter"`
+}
+
+type testSchemaFzOptions struct {
+	Strict bool `json:"strict"`
+}
+
+func (this testSchemaSource) SchemaVariants() (string, string, map[string]any) {
+	return "type", "options", map[string]any{"fz": testSchemaFzOptions{}, "csv": testSchemaCsvOptions{}}
+}
+
+type testSchemaConfig struct {
+	Target  testSchemaTarget            `json:"target" doc:"where to write"`
+	Sources map[string]testSchemaSource `json:"sources" doc:"sources by name"`
+	Layers  []testSchemaTarget          `json:"layers"`
+}
+
+func Test_Schema_NewSchema(t *testing.T) {
+	schema := NewSchema("test", testSchemaConfig{})
+	assert.Equal(t, SchemaDraft, schema["$schema"])
+	assert.Equal(t, "test", schema["title"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := schema["properties"].(map[string]Schema)
+	assert.Contains(t, properties, "$schema")
+	assert.Contains(t, properties, "$doc")
+
+	target := properties["target"]
+	assert.Equal(t, "where to write", target["description"])
+	assert.Equal(t, map[string]Schema{
+		"path":   {"type": "string", "description": "output folder"},
+		"pretty": {"type": "boolean"},
+	}, target["properties"])
+
+	layers := properties["layers"]
+	assert.Equal(t, "array", layers["type"])
+	assert.Equal(t, "object", layers["items"].(Schema)["type"])
+
+	source := properties["sources"]["additionalProperties"].(Schema)
+	assert.Equal(t, map[string]Schema{
+		"name":    {"type": "string", "description": "source name"},
+		"type":    {"type": "string", "description": "source type"},
+		"options": {"description": "source options"},
+		"count":   {"type": "integer", "minimum": 0},
+		"ratio":   {"type": "number"},
+		"tags":    {"type": "object", "additionalProperties": Schema{"type": "integer"}},
+	}, source["properties"])
+
+	assert.Equal(t, []Schema{
+		{
+			"properties": Schema{
+				"type": Schema{"const": "csv"},
+				"options": Schema{
+					"type":                 "object",
+					"properties":           map[string]Schema{"delimiter": {"type": "string"}},
+					"additionalProperties": false,
+				},
+			},
+			"required": []string{"type"},
+		},
+		{
+			"properties": Schema{
+				"type": Schema{"const": "fz"},
+				"options": Schema{
+					"type":                 "object",
+					"properties":           map[string]Schema{"strict": {"type": "boolean"}},
+					"additionalProperties": false,
+				},
+			},
+			"required": []string{"type"},
+		},
+	}, source["oneOf"])
+}
+
+func Test_Schema_FieldDocs(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"target":            "where to write",
+		"target.path":       "output folder",
+		"sources":           "sources by name",
+		"sources.*.name":    "source name",
+		"sources.*.type":    "source type",
+		"sources.*.options": "source options",
+		"layers[].path":     "output folder",
+	}, FieldDocs(testSchemaConfig{}))
+
+	assert.Empty(t, FieldDocs(nil))
+}
@@ -1,13 +1,14 @@
 package misc
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 
 	"irptools/utils/errs"
 )
 
-func LoadJsonConfigFromFile[T errs.Validatable](filePath string, setup func(cfg T) (T, error)) (T, error) {
+func LoadJsonConfigFromFile[T errs.Validatable](filePath string, overrides []ConfigOverride, setup func(cfg T) (T, error)) (T, error) {
 	var cfg T
 
 	cfgData, err := os.ReadFile(filePath)
@@ -15,7 +16,39 @@ func LoadJsonConfigFromFile[T errs.Validatable](filePath string, setup func(cfg
 		return cfg, errs.Wrap(err)
 	}
 
-	err = json.Unmarshal(cfgData, &cfg)
+	return LoadJsonConfig(cfgData, overrides, setup)
+}
+
+// overrides are applied to the json document before it is decoded, validated and adjusted
+func LoadJsonConfig[T errs.Validatable](cfgData []byte, overrides []ConfigOverride, setup func(cfg T) (T, error)) (T, error) {
+	return loadJsonConfig(cfgData, overrides, setup)
+}
+
+func LoadJsonConfigFromFileOrEmpty[T errs.Validatable](filePath string, overrides []ConfigOverride, setup func(cfg T) (T, error)) (T, error) {
+	if filePath == "" {
+		return loadJsonConfig([]byte("{}"), overrides, setup)
+	}
+
+	cfgData, err := os.ReadFile(filePath)
+	if err != nil {
+		var cfg T
+		return cfg, errs.Wrap(err)
+	}
+
+	return loadJsonConfig(cfgData, overrides, setup)
+}
+
+func loadJsonConfig[T errs.Validatable](cfgData []byte, overrides []ConfigOverride, setup func(cfg T) (T, error)) (T, error) {
+	var cfg T
+
+	cfgData, err := preprocessJsonConfig(cfgData, overrides)
+	if err != nil {
+		return cfg, errs.Wrap(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(cfgData))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&cfg)
 	if err != nil {
 		return cfg, errs.Wrap(err)
 	}
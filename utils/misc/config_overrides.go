@@ -0,0 +1,185 @@
+package misc
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"irptools/utils/errs"
+)
+
+type ConfigOverride struct {
+	Path  []string
+	Value any
+	Raw   string
+}
+
+func ParseConfigOverride(str string) (ConfigOverride, error) {
+	path, value, ok := strings.Cut(str, "=")
+	if !ok {
+		return ConfigOverride{}, errs.Errorf("expected 'path.to.field=value': '%s'", str)
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return ConfigOverride{}, errs.Errorf("empty field path: '%s'", str)
+	}
+
+	v, err := unmarshalJsonValue([]byte(value))
+	if err != nil {
+		v = value
+	}
+
+	return ConfigOverride{Path: strings.Split(path, "."), Value: v, Raw: value}, nil
+}
+
+const (
+	ConfigSchemaKey = "$schema"
+	ConfigDocKey    = "$doc"
+)
+
+func preprocessJsonConfig(cfgData []byte, overrides []ConfigOverride) ([]byte, error) {
+	doc, err := unmarshalJsonValue(cfgData)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	if m, ok := doc.(map[string]any); ok {
+		delete(m, ConfigSchemaKey)
+		delete(m, ConfigDocKey)
+	}
+
+	for _, o := range overrides {
+		doc, err = setConfigValue(doc, o.Path, overrideValue(doc, o))
+		if err != nil {
+			return nil, errs.Errorf("failed to set '%s': %w", strings.Join(o.Path, "."), err)
+		}
+	}
+
+	doc, err = expandConfigEnv(doc)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+
+	return json.Marshal(doc)
+}
+
+func unmarshalJsonValue(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var v any
+	err := decoder.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errs.Error("unexpected data after json value")
+	}
+	return v, nil
+}
+
+// a value replacing a string field stays a string, so -set path=2024 works without json quoting
+func overrideValue(doc any, o ConfigOverride) any {
+	if _, ok := o.Value.(string); ok {
+		return o.Value
+	}
+	if _, ok := getConfigValue(doc, o.Path).(string); ok {
+		return o.Raw
+	}
+	return o.Value
+}
+
+func getConfigValue(node any, path []string) any {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			node = n[key]
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(n) {
+				return nil
+			}
+			node = n[idx]
+		default:
+			return nil
+		}
+	}
+	return node
+}
+
+func setConfigValue(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	key := path[0]
+	switch n := node.(type) {
+	case nil:
+		child, err := setConfigValue(nil, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{key: child}, nil
+	case map[string]any:
+		child, err := setConfigValue(n[key], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[key] = child
+		return n, nil
+	case []any:
+		idx, err := strconv.Atoi(key)
+		if err != nil || idx < 0 || idx > len(n) {
+			return nil, errs.Errorf("invalid index '%s' for array of length %d", key, len(n))
+		}
+		if idx == len(n) {
+			n = append(n, nil)
+		}
+		n[idx], err = setConfigValue(n[idx], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+
+	return nil, errs.Errorf("field '%s' is not an object or array", key)
+}
+
+var configEnvRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)}`)
+
+func expandConfigEnv(node any) (any, error) {
+	switch n := node.(type) {
+	case string:
+		var err error
+		expanded := configEnvRegexp.ReplaceAllStringFunc(n, func(match string) string {
+			name := configEnvRegexp.FindStringSubmatch(match)[1]
+			value, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = errs.Errorf("environment variable is not set: '%s'", name)
+			}
+			return value
+		})
+		return expanded, err
+	case map[string]any:
+		for k, v := range n {
+			expanded, err := expandConfigEnv(v)
+			if err != nil {
+				return nil, errs.Errorf("%s: %w", k, err)
+			}
+			n[k] = expanded
+		}
+	case []any:
+		for i, v := range n {
+			expanded, err := expandConfigEnv(v)
+			if err != nil {
+				return nil, errs.Errorf("[%d]: %w", i, err)
+			}
+			n[i] = expanded
+		}
+	}
+	return node, nil
+}
@@ -0,0 +1,188 @@
+package misc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"irptools/utils/errs"
+)
+
+func Test_ConfigOverrides_Parse(t *testing.T) {
+	tests := []struct {
+		str   string
+		path  []string
+		value any
+		fails bool
+	}{
+		{"source=./data", []string{"source"}, "./data", false},
+		{"target.folder.path=./out", []string{"target", "folder", "path"}, "./out", false},
+		{"target.withStat=true", []string{"target", "withStat"}, true, false},
+		{"analyze.minGroupSize=5", []string{"analyze", "minGroupSize"}, json.Number("5"), false},
+		{`filter={"brand":"LG"}`, []string{"filter"}, map[string]any{"brand": "LG"}, false},
+		{"columns=[1,2]", []string{"columns"}, []any{json.Number("1"), json.Number("2")}, false},
+		{"name=a=b", []string{"name"}, "a=b", false},
+		{"name=", []string{"name"}, "", false},
+		{"name", nil, nil, true},
+		{" =value", nil, nil, true},
+	}
+
+	for _, test := range tests {
+		o, err := ParseConfigOverride(test.str)
+		if test.fails {
+			assert.Error(t, err, test.str)
+			continue
+		}
+		assert.NoError(t, err, test.str)
+		assert.Equal(t, test.path, o.Path, test.str)
+		assert.Equal(t, test.value, o.Value, test.str)
+	}
+}
+
+func Test_ConfigOverrides_SetValue(t *testing.T) {
+	tests := []struct {
+		doc      string
+		path     []string
+		value    any
+		expected string
+	}{
+		{`{}`, []string{"a"}, "x", `{"a":"x"}`},
+		{`{"a":1}`, []string{"a"}, "x", `{"a":"x"}`},
+		{`{}`, []string{"a", "b", "c"}, true, `{"a":{"b":{"c":true}}}`},
+		{`{"a":{"b":1,"c":2}}`, []string{"a", "b"}, 3, `{"a":{"b":3,"c":2}}`},
+		{`{"a":[1,2]}`, []string{"a", "0"}, 5, `{"a":[5,2]}`},
+		{`{"a":[1,2]}`, []string{"a", "2"}, 3, `{"a":[1,2,3]}`},
+		{`{"a":[]}`, []string{"a", "0", "b"}, "x", `{"a":[{"b":"x"}]}`},
+		{`{"a":[{"b":1}]}`, []string{"a", "0", "b"}, 2, `{"a":[{"b":2}]}`},
+		{`{"a":[{"b":1}]}`, []string{"a", "1", "b"}, 2, `{"a":[{"b":1},{"b":2}]}`},
+	}
+
+	for _, test := range tests {
+		doc, err := unmarshalJsonValue([]byte(test.doc))
+		assert.NoError(t, err, test.doc)
+
+		doc, err = setConfigValue(doc, test.path, test.value)
+		assert.NoError(t, err, test.doc)
+
+		actual, err := json.Marshal(doc)
+		assert.NoError(t, err, test.doc)
+		assert.JSONEq(t, test.expected, string(actual), test.doc)
+	}
+}
+
+func Test_ConfigOverrides_SetValueErrors(t *testing.T) {
+	tests := []struct {
+		doc  string
+		path []string
+	}{
+		{`{"a":[1,2]}`, []string{"a", "3"}},
+		{`{"a":[1,2]}`, []string{"a", "-1"}},
+		{`{"a":[1,2]}`, []string{"a", "x"}},
+		{`{"a":"x"}`, []string{"a", "b"}},
+		{`{"a":1}`, []string{"a", "0"}},
+	}
+
+	for _, test := range tests {
+		doc, err := unmarshalJsonValue([]byte(test.doc))
+		assert.NoError(t, err, test.doc)
+
+		_, err = setConfigValue(doc, test.path, "v")
+		assert.Error(t, err, test.doc)
+	}
+}
+
+func Test_ConfigOverrides_ExpandEnv(t *testing.T) {
+	t.Setenv("IRPTOOLS_TEST_DIR", "/data")
+	t.Setenv("IRPTOOLS_TEST_EMPTY", "")
+
+	tests := []struct {
+		doc      string
+		expected string
+		fails    bool
+	}{
+		{`{"a":"${IRPTOOLS_TEST_DIR}/fz"}`, `{"a":"/data/fz"}`, false},
+		{`{"a":"x${IRPTOOLS_TEST_EMPTY}y"}`, `{"a":"xy"}`, false},
+		{`{"a":["${IRPTOOLS_TEST_DIR}",1,true]}`, `{"a":["/data",1,true]}`, false},
+		{`{"a":{"b":"${IRPTOOLS_TEST_DIR}"}}`, `{"a":{"b":"/data"}}`, false},
+		{`{"a":"$IRPTOOLS_TEST_DIR"}`, `{"a":"$IRPTOOLS_TEST_DIR"}`, false},
+		{`{"a":"${IRPTOOLS_TEST_UNSET}"}`, "", true},
+		{`{"a":{"b":["${IRPTOOLS_TEST_UNSET}"]}}`, "", true},
+	}
+
+	for _, test := range tests {
+		doc, err := unmarshalJsonValue([]byte(test.doc))
+		assert.NoError(t, err, test.doc)
+
+		doc, err = expandConfigEnv(doc)
+		if test.fails {
+			assert.Error(t, err, test.doc)
+			continue
+		}
+		assert.NoError(t, err, test.doc)
+
+		actual, err := json.Marshal(doc)
+		assert.NoError(t, err, test.doc)
+		assert.JSONEq(t, test.expected, string(actual), test.doc)
+	}
+}
+
+type testOverriddenConfig struct {
+	Path   string `json:"path"`
+	Limit  int    `json:"limit"`
+	Pretty bool   `json:"pretty"`
+}
+
+func (this testOverriddenConfig) Validate() error {
+	if this.Path == "" {
+		return errs.Error("empty path")
+	}
+	return nil
+}
+
+func Test_ConfigOverrides_LoadJsonConfig(t *testing.T) {
+	mustParse := func(str string) ConfigOverride {
+		o, err := ParseConfigOverride(str)
+		assert.NoError(t, err, str)
+		return o
+	}
+
+	tests := []struct {
+		name      string
+		doc       string
+		overrides []string
+		expected  testOverriddenConfig
+		fails     bool
+	}{
+		{"no overrides", `{"path":"./out","limit":1}`, nil, testOverriddenConfig{Path: "./out/adjusted", Limit: 1}, false},
+		{"override fixes invalid", `{"limit":1}`, []string{"path=./set"}, testOverriddenConfig{Path: "./set/adjusted", Limit: 1}, false},
+		{"override breaks valid", `{"path":"./out"}`, []string{"path="}, testOverriddenConfig{}, true},
+		{"typed value", `{"path":"./out"}`, []string{"limit=5", "pretty=true"}, testOverriddenConfig{Path: "./out/adjusted", Limit: 5, Pretty: true}, false},
+		{"number into string field", `{"path":"./out"}`, []string{"path=2024"}, testOverriddenConfig{Path: "2024/adjusted"}, false},
+		{"quoted string", `{}`, []string{`path="2024"`}, testOverriddenConfig{Path: "2024/adjusted"}, false},
+		{"number into missing string field", `{}`, []string{"path=2024"}, testOverriddenConfig{}, true},
+		{"later override wins", `{"path":"./out"}`, []string{"path=./a", "path=./b"}, testOverriddenConfig{Path: "./b/adjusted"}, false},
+	}
+
+	for _, test := range tests {
+		overrides := []ConfigOverride{}
+		for _, str := range test.overrides {
+			overrides = append(overrides, mustParse(str))
+		}
+
+		adjusted := 0
+		cfg, err := LoadJsonConfig([]byte(test.doc), overrides, func(cfg testOverriddenConfig) (testOverriddenConfig, error) {
+			adjusted++
+			cfg.Path += "/adjusted"
+			return cfg, nil
+		})
+		if test.fails {
+			assert.Error(t, err, test.name)
+			assert.Equal(t, 0, adjusted, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, cfg, test.name)
+		assert.Equal(t, 1, adjusted, test.name)
+	}
+}